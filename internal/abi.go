@@ -0,0 +1,326 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// loadABI parses the contract ABI either from an inline json string or from abiFile.
+// abiFile may contain a plain ABI json array or a Hardhat/Foundry/Truffle build artifact (json object with "abi" field).
+func loadABI(abiJSON string, abiFile string) (abi.ABI, error) {
+	if abiFile == "" {
+		parsedABI, err := abi.JSON(strings.NewReader(abiJSON))
+		if err != nil {
+			return abi.ABI{}, fmt.Errorf("failed to parse contract ABI: %w", err)
+		}
+		return parsedABI, nil
+	}
+
+	data, err := os.ReadFile(abiFile)
+	if err != nil {
+		return abi.ABI{}, fmt.Errorf("failed to read ABI file '%s': %w", abiFile, err)
+	}
+
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '{' {
+		var artifact struct {
+			ABI json.RawMessage `json:"abi"`
+		}
+		if err = json.Unmarshal(data, &artifact); err != nil {
+			return abi.ABI{}, fmt.Errorf("failed to parse build artifact '%s': %w", abiFile, err)
+		}
+		if len(artifact.ABI) == 0 {
+			return abi.ABI{}, fmt.Errorf("build artifact '%s' has no \"abi\" field", abiFile)
+		}
+		data = artifact.ABI
+	}
+
+	parsedABI, err := abi.JSON(bytes.NewReader(data))
+	if err != nil {
+		return abi.ABI{}, fmt.Errorf("failed to parse contract ABI from '%s': %w", abiFile, err)
+	}
+	return parsedABI, nil
+}
+
+// findMethod looks up a method either by bare name (e.g. "transfer") or by signature (e.g. "transfer(address,uint256)").
+// A bare name of an overloaded function is rejected, as it's ambiguous.
+func findMethod(parsedABI abi.ABI, name string) (abi.Method, error) {
+	name = strings.Join(strings.Fields(name), "")
+
+	if strings.Contains(name, "(") {
+		for _, method := range parsedABI.Methods {
+			if method.Sig == name {
+				return method, nil
+			}
+		}
+		return abi.Method{}, fmt.Errorf("invalid method signature: %s (ensure the method exists in the contract ABI)", name)
+	}
+
+	var found []abi.Method
+	for _, method := range parsedABI.Methods {
+		if method.RawName == name {
+			found = append(found, method)
+		}
+	}
+
+	switch len(found) {
+	case 0:
+		return abi.Method{}, fmt.Errorf("invalid method name: %s (ensure the method exists in the contract ABI)", name)
+	case 1:
+		return found[0], nil
+	default:
+		signatures := make([]string, len(found))
+		for i, method := range found {
+			signatures[i] = method.Sig
+		}
+		sort.Strings(signatures)
+		return abi.Method{}, fmt.Errorf("method %s is overloaded, use one of the signatures: %s", name, strings.Join(signatures, ", "))
+	}
+}
+
+// convertParams converts config (yaml) values into go values accepted by abi.Pack for the given arguments.
+func convertParams(args abi.Arguments, params []interface{}) ([]interface{}, error) {
+	if len(args) != len(params) {
+		return nil, fmt.Errorf("parameter count mismatch: expected %d, got %d", len(args), len(params))
+	}
+
+	convertedParams := make([]interface{}, len(params))
+	for i, arg := range args {
+		path := fmt.Sprintf("params[%d]", i)
+		if arg.Name != "" {
+			path = fmt.Sprintf("params[%d](%s)", i, arg.Name)
+		}
+
+		value, err := convertValue(arg.Type, params[i], path)
+		if err != nil {
+			return nil, err
+		}
+		convertedParams[i] = value.Interface()
+	}
+
+	return convertedParams, nil
+}
+
+// convertValue recursively converts param into a value of the go type that matches abi type typ.
+// path is used in errors to point at the exact (nested) parameter.
+func convertValue(typ abi.Type, param interface{}, path string) (reflect.Value, error) {
+	switch typ.T {
+	case abi.AddressTy:
+		str, ok := param.(string)
+		if !ok || !common.IsHexAddress(str) {
+			return reflect.Value{}, fmt.Errorf("%s: must be a valid Ethereum address (hex string), got %v", path, param)
+		}
+		return reflect.ValueOf(common.HexToAddress(str)), nil
+
+	case abi.UintTy, abi.IntTy:
+		value, err := parseBigInt(param)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("%s: %w", path, err)
+		}
+		if err = checkIntRange(value, typ.T == abi.UintTy, typ.Size); err != nil {
+			return reflect.Value{}, fmt.Errorf("%s: %w", path, err)
+		}
+
+		goType := typ.GetType()
+		switch goType.Kind() {
+		case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return reflect.ValueOf(value.Int64()).Convert(goType), nil
+		case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return reflect.ValueOf(value.Uint64()).Convert(goType), nil
+		default:
+			return reflect.ValueOf(value), nil
+		}
+
+	case abi.BoolTy:
+		switch v := param.(type) {
+		case bool:
+			return reflect.ValueOf(v), nil
+		case string:
+			value, err := strconv.ParseBool(v)
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("%s: must be a boolean (true/false or 'true'/'false'), got %q", path, v)
+			}
+			return reflect.ValueOf(value), nil
+		default:
+			return reflect.Value{}, fmt.Errorf("%s: must be a boolean, got %v", path, param)
+		}
+
+	case abi.StringTy:
+		str, ok := param.(string)
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("%s: must be a string, got %v", path, param)
+		}
+		return reflect.ValueOf(str), nil
+
+	case abi.BytesTy:
+		data, err := parseHexBytes(param)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("%s: %w", path, err)
+		}
+		return reflect.ValueOf(data), nil
+
+	case abi.FixedBytesTy, abi.FunctionTy:
+		data, err := parseHexBytes(param)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("%s: %w", path, err)
+		}
+		goType := typ.GetType()
+		if len(data) != goType.Len() {
+			return reflect.Value{}, fmt.Errorf("%s: expected %d bytes for %s, got %d", path, goType.Len(), typ.String(), len(data))
+		}
+		value := reflect.New(goType).Elem()
+		reflect.Copy(value, reflect.ValueOf(data))
+		return value, nil
+
+	case abi.SliceTy, abi.ArrayTy:
+		items, ok := param.([]interface{})
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("%s: must be a list for %s, got %v", path, typ.String(), param)
+		}
+
+		var value reflect.Value
+		if typ.T == abi.ArrayTy {
+			if len(items) != typ.Size {
+				return reflect.Value{}, fmt.Errorf("%s: expected %d elements for %s, got %d", path, typ.Size, typ.String(), len(items))
+			}
+			value = reflect.New(typ.GetType()).Elem()
+		} else {
+			value = reflect.MakeSlice(typ.GetType(), len(items), len(items))
+		}
+
+		for i, item := range items {
+			elem, err := convertValue(*typ.Elem, item, fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			value.Index(i).Set(elem)
+		}
+		return value, nil
+
+	case abi.TupleTy:
+		value := reflect.New(typ.TupleType).Elem()
+
+		switch fields := param.(type) {
+		case map[string]interface{}:
+			if len(fields) != len(typ.TupleElems) {
+				return reflect.Value{}, fmt.Errorf("%s: expected %d fields for tuple, got %d", path, len(typ.TupleElems), len(fields))
+			}
+			for i, elemType := range typ.TupleElems {
+				name := typ.TupleRawNames[i]
+				field, exists := fields[name]
+				if !exists {
+					return reflect.Value{}, fmt.Errorf("%s: missing tuple field '%s'", path, name)
+				}
+				elem, err := convertValue(*elemType, field, path+"."+name)
+				if err != nil {
+					return reflect.Value{}, err
+				}
+				value.Field(i).Set(elem)
+			}
+		case []interface{}:
+			if len(fields) != len(typ.TupleElems) {
+				return reflect.Value{}, fmt.Errorf("%s: expected %d fields for tuple, got %d", path, len(typ.TupleElems), len(fields))
+			}
+			for i, elemType := range typ.TupleElems {
+				elem, err := convertValue(*elemType, fields[i], path+"."+typ.TupleRawNames[i])
+				if err != nil {
+					return reflect.Value{}, err
+				}
+				value.Field(i).Set(elem)
+			}
+		default:
+			return reflect.Value{}, fmt.Errorf("%s: tuple must be a map of fields or a list, got %v", path, param)
+		}
+		return value, nil
+
+	default:
+		return reflect.Value{}, fmt.Errorf("%s: unsupported parameter type %s", path, typ.String())
+	}
+}
+
+// parseBigInt parses yaml integers and decimal or 0x-prefixed hex strings.
+func parseBigInt(param interface{}) (*big.Int, error) {
+	switch v := param.(type) {
+	case int:
+		return big.NewInt(int64(v)), nil
+	case int64:
+		return big.NewInt(v), nil
+	case uint64:
+		return new(big.Int).SetUint64(v), nil
+	case float64:
+		// yaml decodes integers that overflow int64/uint64 as floats, precision is lost beyond 2^53
+		if v != math.Trunc(v) || math.Abs(v) > 1<<53 {
+			return nil, fmt.Errorf("number %v can't be represented exactly, use a quoted string", v)
+		}
+		return big.NewInt(int64(v)), nil
+	case string:
+		str := strings.TrimSpace(v)
+		negative := strings.HasPrefix(str, "-")
+		str = strings.TrimPrefix(str, "-")
+
+		base := 10
+		if strings.HasPrefix(str, "0x") || strings.HasPrefix(str, "0X") {
+			base = 16
+			str = str[2:]
+		}
+
+		value, ok := new(big.Int).SetString(str, base)
+		if !ok || str == "" {
+			return nil, fmt.Errorf("invalid integer value %q", v)
+		}
+		if negative {
+			value.Neg(value)
+		}
+		return value, nil
+	default:
+		return nil, fmt.Errorf("must be an integer or a numeric string, got %v", param)
+	}
+}
+
+// checkIntRange verifies value fits into (u)int<size>.
+func checkIntRange(value *big.Int, unsigned bool, size int) error {
+	if unsigned {
+		if value.Sign() < 0 {
+			return fmt.Errorf("negative value %s for uint%d", value, size)
+		}
+		if value.BitLen() > size {
+			return fmt.Errorf("value %s overflows uint%d", value, size)
+		}
+		return nil
+	}
+
+	limit := new(big.Int).Lsh(big.NewInt(1), uint(size-1))
+	minValue := new(big.Int).Neg(limit)
+	maxValue := new(big.Int).Sub(limit, big.NewInt(1))
+	if value.Cmp(minValue) < 0 || value.Cmp(maxValue) > 0 {
+		return fmt.Errorf("value %s overflows int%d", value, size)
+	}
+	return nil
+}
+
+// parseHexBytes decodes a 0x-prefixed (or bare) hex string.
+func parseHexBytes(param interface{}) ([]byte, error) {
+	str, ok := param.(string)
+	if !ok {
+		return nil, fmt.Errorf("must be a hex string, got %v", param)
+	}
+
+	str = strings.TrimPrefix(strings.TrimPrefix(str, "0x"), "0X")
+	data, err := hex.DecodeString(str)
+	if err != nil {
+		return nil, fmt.Errorf("invalid hex string %q: %w", param, err)
+	}
+	return data, nil
+}
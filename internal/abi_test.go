@@ -0,0 +1,209 @@
+package internal
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+func TestParseBigInt(t *testing.T) {
+	tests := []struct {
+		name  string
+		param interface{}
+		want  string
+		error bool
+	}{
+		{"int", 42, "42", false},
+		{"int64", int64(-7), "-7", false},
+		{"uint64", uint64(1) << 63, "9223372036854775808", false},
+		{"decimal string", " 1000000000000000000000 ", "1000000000000000000000", false},
+		{"negative decimal string", "-15", "-15", false},
+		{"hex string", "0xff", "255", false},
+		{"upper hex prefix", "0XFF", "255", false},
+		{"negative hex string", "-0x10", "-16", false},
+		{"float integer", float64(1 << 53), "9007199254740992", false},
+		{"float above 2^53", float64(1 << 54), "", true},
+		{"fractional float", 1.5, "", true},
+		{"empty hex", "0x", "", true},
+		{"not a number", "abc", "", true},
+		{"bool", true, "", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			value, err := parseBigInt(test.param)
+			if test.error {
+				if err == nil {
+					t.Fatalf("expected error, got %s", value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if value.String() != test.want {
+				t.Fatalf("got %s, want %s", value, test.want)
+			}
+		})
+	}
+}
+
+func TestCheckIntRange(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		unsigned bool
+		size     int
+		error    bool
+	}{
+		{"uint8 max", "255", true, 8, false},
+		{"uint8 overflow", "256", true, 8, true},
+		{"uint negative", "-1", true, 256, true},
+		{"uint256 max", new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1)).String(), true, 256, false},
+		{"int8 max", "127", false, 8, false},
+		{"int8 min", "-128", false, 8, false},
+		{"int8 overflow", "128", false, 8, true},
+		{"int8 underflow", "-129", false, 8, true},
+		{"int256 min", new(big.Int).Neg(new(big.Int).Lsh(big.NewInt(1), 255)).String(), false, 256, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			value, _ := new(big.Int).SetString(test.value, 10)
+			err := checkIntRange(value, test.unsigned, test.size)
+			if test.error != (err != nil) {
+				t.Fatalf("error: %v, expected error: %v", err, test.error)
+			}
+		})
+	}
+}
+
+func TestConvertValue(t *testing.T) {
+	tupleType, err := abi.NewType("tuple", "", []abi.ArgumentMarshaling{
+		{Name: "owner", Type: "address"},
+		{Name: "amounts", Type: "uint8[]"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		typ   string
+		param interface{}
+		want  string // printed go value
+		error string // expected error fragment
+	}{
+		{"uint8", "uint8", 200, "200", ""},
+		{"int16 negative hex", "int16", "-0x8000", "-32768", ""},
+		{"int16 overflow", "int16", "0x8000", "", "params[0]: value 32768 overflows int16"},
+		{"uint256 quoted", "uint256", "115792089237316195423570985008687907853269984665640564039457584007913129639935", "115792089237316195423570985008687907853269984665640564039457584007913129639935", ""},
+		{"uint64 float above 2^53", "uint64", float64(1 << 60), "", "can't be represented exactly"},
+		{"bool string", "bool", "true", "true", ""},
+		{"bytes2", "bytes2", "0xbeef", "[190 239]", ""},
+		{"bytes2 wrong size", "bytes2", "0xbe", "", "expected 2 bytes for bytes2, got 1"},
+		{"fixed array size", "uint8[2]", []interface{}{1}, "", "expected 2 elements for uint8[2], got 1"},
+		{"nested array path", "uint8[][]", []interface{}{[]interface{}{1}, []interface{}{2, 300}}, "", "params[0][1][1]: value 300 overflows uint8"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			typ, err := abi.NewType(test.typ, "", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkConverted(t, typ, test.param, test.want, test.error)
+		})
+	}
+
+	tupleTests := []struct {
+		name  string
+		param interface{}
+		error string
+	}{
+		{"tuple map", map[string]interface{}{"owner": "0x00000000000000000000000000000000000000aa", "amounts": []interface{}{1, 2}}, ""},
+		{"tuple list", []interface{}{"0x00000000000000000000000000000000000000aa", []interface{}{1}}, ""},
+		{"tuple nested path", map[string]interface{}{"owner": "0x00000000000000000000000000000000000000aa", "amounts": []interface{}{1, -1}}, "params[0].amounts[1]: negative value -1 for uint8"},
+		{"tuple missing field", map[string]interface{}{"owner": "0x00000000000000000000000000000000000000aa", "other": 1}, "params[0]: missing tuple field 'amounts'"},
+		{"tuple invalid address", []interface{}{"0x01", []interface{}{}}, "params[0].owner: must be a valid Ethereum address"},
+	}
+
+	for _, test := range tupleTests {
+		t.Run(test.name, func(t *testing.T) {
+			checkConverted(t, tupleType, test.param, "", test.error)
+		})
+	}
+}
+
+// checkConverted converts param to typ and checks the printed value or the error fragment.
+func checkConverted(t *testing.T, typ abi.Type, param interface{}, want string, wantErr string) {
+	t.Helper()
+
+	value, err := convertValue(typ, param, "params[0]")
+	if wantErr != "" {
+		if err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Fatalf("got error %v, want %q", err, wantErr)
+		}
+		return
+	}
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want != "" {
+		if got := fmt.Sprint(value.Interface()); got != want {
+			t.Fatalf("got %s, want %s", got, want)
+		}
+	}
+
+	// converted value must be accepted by the ABI encoder
+	if _, err = (abi.Arguments{{Type: typ}}).Pack(value.Interface()); err != nil {
+		t.Fatalf("converted value can't be packed: %v", err)
+	}
+}
+
+func TestFindMethod(t *testing.T) {
+	parsedABI, err := loadABI(`[
+		{"type":"function","name":"transfer","inputs":[{"name":"to","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[]},
+		{"type":"function","name":"mint","inputs":[{"name":"amount","type":"uint256"}],"outputs":[]},
+		{"type":"function","name":"mint","inputs":[{"name":"to","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[]}
+	]`, "")
+	if err != nil {
+		t.Fatalf("failed to load ABI: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		want  string // expected method signature
+		error string
+	}{
+		{"bare name", "transfer", "transfer(address,uint256)", ""},
+		{"signature", "transfer(address,uint256)", "transfer(address,uint256)", ""},
+		{"signature with spaces", " transfer( address, uint256 ) ", "transfer(address,uint256)", ""},
+		{"overloaded signature", "mint(uint256)", "mint(uint256)", ""},
+		{"overloaded bare name", "mint", "", "method mint is overloaded, use one of the signatures: mint(address,uint256), mint(uint256)"},
+		{"unknown name", "burn", "", "invalid method name: burn"},
+		{"unknown signature", "transfer(address)", "", "invalid method signature: transfer(address)"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			method, err := findMethod(parsedABI, test.input)
+			if test.error != "" {
+				if err == nil || !strings.Contains(err.Error(), test.error) {
+					t.Fatalf("got error %v, want %q", err, test.error)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if method.Sig != test.want {
+				t.Fatalf("got %s, want %s", method.Sig, test.want)
+			}
+		})
+	}
+}
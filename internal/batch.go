@@ -0,0 +1,132 @@
+package internal
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const DefaultBatchFlushIntervalMs = 50
+
+// batchRequest is a single request of a JSON-RPC batch, callback receives the item error and the batch round trip time
+// of the last attempt (retry backoff isn't included).
+type batchRequest struct {
+	elem     rpc.BatchElem
+	callback func(err error, latency time.Duration)
+}
+
+// rpcBatcher groups JSON-RPC requests into batches.
+// A batch is sent when it reaches the size or on flush interval, results are delivered to callbacks asynchronously.
+type rpcBatcher struct {
+	client   *RpcClient
+	size     int
+	interval time.Duration
+
+	mu       sync.Mutex
+	pending  []*batchRequest
+	inflight sync.WaitGroup
+	stop     chan struct{}
+	stopped  chan struct{}
+}
+
+// isEnabled reports whether requests of the test are sent in batches.
+func (c BatchConfig) isEnabled() bool {
+	return c.Size > 1
+}
+
+func newRPCBatcher(client *RpcClient, config BatchConfig) *rpcBatcher {
+	interval := time.Duration(config.FlushIntervalMs) * time.Millisecond
+	if interval <= 0 {
+		interval = DefaultBatchFlushIntervalMs * time.Millisecond
+	}
+
+	b := &rpcBatcher{
+		client:   client,
+		size:     config.Size,
+		interval: interval,
+		stop:     make(chan struct{}),
+		stopped:  make(chan struct{}),
+	}
+	go b.flushLoop()
+
+	return b
+}
+
+// submit queues the request, result must be a pointer the response is decoded into.
+func (b *rpcBatcher) submit(method string, result interface{}, callback func(err error, latency time.Duration), args ...interface{}) {
+	b.mu.Lock()
+	b.pending = append(b.pending, &batchRequest{
+		elem:     rpc.BatchElem{Method: method, Args: args, Result: result},
+		callback: callback,
+	})
+	var batch []*batchRequest
+	if len(b.pending) >= b.size {
+		batch = b.pending
+		b.pending = nil
+	}
+	b.mu.Unlock()
+
+	if batch != nil {
+		b.send(batch)
+	}
+}
+
+// close sends the queued requests and waits until all callbacks are done.
+func (b *rpcBatcher) close() {
+	close(b.stop)
+	<-b.stopped
+	b.inflight.Wait()
+}
+
+func (b *rpcBatcher) flushLoop() {
+	defer close(b.stopped)
+
+	ticker := time.NewTicker(b.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			b.flush()
+		case <-b.stop:
+			b.flush()
+			return
+		}
+	}
+}
+
+func (b *rpcBatcher) flush() {
+	b.mu.Lock()
+	batch := b.pending
+	b.pending = nil
+	b.mu.Unlock()
+
+	if len(batch) != 0 {
+		b.send(batch)
+	}
+}
+
+// send sends the batch in background and maps the batch or per-item error back to each request.
+func (b *rpcBatcher) send(batch []*batchRequest) {
+	b.inflight.Add(1)
+	go func() {
+		defer b.inflight.Done()
+
+		elems := make([]rpc.BatchElem, len(batch))
+		for i, request := range batch {
+			elems[i] = request.elem
+		}
+
+		latency, err := b.client.batchCallTimed(context.Background(), elems)
+
+		for i, request := range batch {
+			if err != nil {
+				request.callback(err, latency)
+			} else {
+				request.callback(elems[i].Error, latency)
+			}
+		}
+	}()
+}
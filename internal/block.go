@@ -0,0 +1,248 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	// DefaultHeaderBatchSize is the batch size of block header requests of tests without batching.
+	DefaultHeaderBatchSize = 100
+	// MaxBlockDetailsRows limits the block details table on stdout, the csv file has all blocks.
+	MaxBlockDetailsRows = 50
+)
+
+// BlockMetrics describes how a block of the test window was filled.
+type BlockMetrics struct {
+	number       uint64
+	timestamp    uint64
+	timeDelta    int64 // in sec, since the previous block, -1 for the first block of the window
+	txs          int
+	testTxs      int
+	gasUsed      uint64
+	gasLimit     uint64
+	baseFee      *big.Int // nil before London
+	senderNonces []*SenderNonceRange
+}
+
+// SenderNonceRange is the range of nonces of test txs of a sender mined in a block.
+type SenderNonceRange struct {
+	sender   string
+	minNonce uint64
+	maxNonce uint64
+}
+
+// rpcBlockHeader is a block of eth_getBlockByNumber without tx bodies, hash is taken from the node as is.
+type rpcBlockHeader struct {
+	Hash         common.Hash    `json:"hash"`
+	Number       hexutil.Uint64 `json:"number"`
+	Timestamp    hexutil.Uint64 `json:"timestamp"`
+	GasUsed      hexutil.Uint64 `json:"gasUsed"`
+	GasLimit     hexutil.Uint64 `json:"gasLimit"`
+	BaseFee      *hexutil.Big   `json:"baseFeePerGas"`
+	Transactions []common.Hash  `json:"transactions"`
+}
+
+// fetchBlockHeaders fetches blocks without tx bodies in JSON-RPC batches, at most RpcCallPerSecond blocks per second.
+// Blocks the node doesn't have yet are missing in the result.
+func (t *Test) fetchBlockHeaders(ctx context.Context, numbers []uint64) (map[uint64]*rpcBlockHeader, error) {
+	config := t.batch
+	if !config.isEnabled() {
+		config = BatchConfig{Size: DefaultHeaderBatchSize}
+	}
+	batcher := newRPCBatcher(t.client, config)
+	ticker := time.NewTicker(time.Second * time.Duration(config.Size) / RpcCallPerSecond)
+	defer ticker.Stop()
+
+	var mu sync.Mutex
+	var fetchErr error
+	headers := make(map[uint64]*rpcBlockHeader, len(numbers))
+	for i, number := range numbers {
+		if i != 0 && i%config.Size == 0 {
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+			}
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		header := new(*rpcBlockHeader)
+		batcher.submit("eth_getBlockByNumber", header, func(err error, latency time.Duration) {
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil && fetchErr == nil {
+				fetchErr = fmt.Errorf("failed to fetch block %d: %w", number, err)
+			}
+			if err == nil && *header != nil {
+				headers[number] = *header
+			}
+		}, hexutil.EncodeUint64(number), false)
+	}
+	batcher.close()
+
+	if fetchErr == nil {
+		fetchErr = ctx.Err()
+	}
+
+	return headers, fetchErr
+}
+
+// fetchWindowBlocks fetches blocks of the test window (from start block to the last tracked or mined block) without tx bodies,
+// so the block report shows empty and partially filled blocks too. Blocks with test txs are taken from t.blocks.
+func (t *Test) fetchWindowBlocks(ctx context.Context) error {
+	if len(t.blocks) == 0 && t.endBlock <= t.startBlock {
+		return nil
+	}
+
+	fetched := make(map[uint64]*types.Block, len(t.blocks))
+	lastBlock := t.endBlock
+	for _, block := range t.blocks {
+		fetched[block.NumberU64()] = block
+		lastBlock = max(lastBlock, block.NumberU64())
+	}
+
+	var numbers []uint64
+	for number := t.startBlock; number <= lastBlock; number++ {
+		if fetched[number] == nil {
+			numbers = append(numbers, number)
+		}
+	}
+	headers, err := t.fetchBlockHeaders(ctx, numbers)
+
+	// blocks fetched before the error are still reported
+	for number := t.startBlock; number <= lastBlock; number++ {
+		if block := fetched[number]; block != nil {
+			t.windowBlocks = append(t.windowBlocks, newRpcBlockHeader(block))
+		} else if header, exists := headers[number]; exists {
+			t.windowBlocks = append(t.windowBlocks, header)
+		}
+	}
+
+	if err != nil {
+		return fmt.Errorf("test '%s': failed to fetch blocks of the test window: %w", t.testName, err)
+	}
+
+	return nil
+}
+
+// newRpcBlockHeader describes the fetched block like eth_getBlockByNumber without tx bodies.
+func newRpcBlockHeader(block *types.Block) *rpcBlockHeader {
+	header := &rpcBlockHeader{
+		Hash:      block.Hash(),
+		Number:    hexutil.Uint64(block.NumberU64()),
+		Timestamp: hexutil.Uint64(block.Time()),
+		GasUsed:   hexutil.Uint64(block.GasUsed()),
+		GasLimit:  hexutil.Uint64(block.GasLimit()),
+		BaseFee:   (*hexutil.Big)(block.BaseFee()),
+	}
+	for _, tx := range block.Transactions() {
+		header.Transactions = append(header.Transactions, tx.Hash())
+	}
+
+	return header
+}
+
+// collectBlockMetrics describes every fetched block of the test window, in block order.
+func (t *Test) collectBlockMetrics() []*BlockMetrics {
+	// nonce ranges of mined test txs by block and sender
+	nonces := make(map[uint64]map[string]*SenderNonceRange)
+	testTxs := make(map[uint64]int)
+	for sender, senderTxs := range t.senderTransactions {
+		for _, tx := range senderTxs {
+			if tx.receipt == nil {
+				continue
+			}
+
+			number := tx.receipt.BlockNumber.Uint64()
+			testTxs[number]++
+			if nonces[number] == nil {
+				nonces[number] = make(map[string]*SenderNonceRange)
+			}
+			nonce := tx.clientTransaction.Nonce()
+			if nonceRange, exists := nonces[number][sender]; exists {
+				nonceRange.minNonce = min(nonceRange.minNonce, nonce)
+				nonceRange.maxNonce = max(nonceRange.maxNonce, nonce)
+			} else {
+				nonces[number][sender] = &SenderNonceRange{sender: sender, minNonce: nonce, maxNonce: nonce}
+			}
+		}
+	}
+
+	blocksMetrics := make([]*BlockMetrics, 0, len(t.windowBlocks))
+	var previous *rpcBlockHeader
+	for _, block := range t.windowBlocks {
+		metrics := &BlockMetrics{
+			number:    uint64(block.Number),
+			timestamp: uint64(block.Timestamp),
+			timeDelta: -1,
+			txs:       len(block.Transactions),
+			gasUsed:   uint64(block.GasUsed),
+			gasLimit:  uint64(block.GasLimit),
+			baseFee:   block.BaseFee.ToInt(),
+			testTxs:   testTxs[uint64(block.Number)],
+		}
+		if previous != nil && previous.Number+1 == block.Number {
+			metrics.timeDelta = int64(block.Timestamp) - int64(previous.Timestamp)
+		}
+		previous = block
+
+		for _, nonceRange := range nonces[metrics.number] {
+			metrics.senderNonces = append(metrics.senderNonces, nonceRange)
+		}
+		sort.Slice(metrics.senderNonces, func(i, j int) bool {
+			return metrics.senderNonces[i].sender < metrics.senderNonces[j].sender
+		})
+
+		blocksMetrics = append(blocksMetrics, metrics)
+	}
+
+	return blocksMetrics
+}
+
+// utilization returns gas used in percent of the block gas limit.
+func (b *BlockMetrics) utilization() float64 {
+	if b.gasLimit == 0 {
+		return 0
+	}
+	return float64(b.gasUsed) / float64(b.gasLimit) * 100
+}
+
+func (b *BlockMetrics) timeDeltaString() string {
+	if b.timeDelta < 0 {
+		return "-"
+	}
+	return strconv.FormatInt(b.timeDelta, 10)
+}
+
+func (b *BlockMetrics) baseFeeGwei() string {
+	if b.baseFee == nil {
+		return "-"
+	}
+	gwei, _ := new(big.Float).Quo(new(big.Float).SetInt(b.baseFee), big.NewFloat(1e9)).Float64()
+	return strconv.FormatFloat(gwei, 'f', 3, 64)
+}
+
+// senderNoncesString formats nonce ranges as "sender:min-max" joined by separator, short shows first 10 address chars.
+func (b *BlockMetrics) senderNoncesString(separator string, short bool) string {
+	ranges := make([]string, 0, len(b.senderNonces))
+	for _, nonceRange := range b.senderNonces {
+		sender := nonceRange.sender
+		if short && len(sender) > 10 {
+			sender = sender[:10]
+		}
+		ranges = append(ranges, fmt.Sprintf("%s:%d-%d", sender, nonceRange.minNonce, nonceRange.maxNonce))
+	}
+	return strings.Join(ranges, separator)
+}
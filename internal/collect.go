@@ -0,0 +1,325 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const LeftoverAttemptsToCollect = 2
+
+// receiptCollector matches receipts of new blocks to sent txs while the test is sending,
+// so after sending only txs still in flight have to be waited for.
+// Txs are tracked before they are sent, a tx mined before its send call returned is not missed.
+type receiptCollector struct {
+	test *Test
+
+	mu      sync.Mutex
+	pending map[common.Hash]*Transaction   // hashes of tracked txs without receipt
+	hashes  map[*Transaction][]common.Hash // tracked hashes of each tx (original and replacement)
+	blocks  map[common.Hash]*types.Block   // blocks with our txs, nil until fetched
+
+	next                   uint64 // next block to walk
+	blockReceiptsSupported bool
+	tracked                atomic.Int32
+	collected              atomic.Int32
+
+	stop    chan struct{}
+	stopped chan struct{}
+}
+
+func newReceiptCollector(test *Test, startBlock uint64) *receiptCollector {
+	return &receiptCollector{
+		test:                   test,
+		pending:                make(map[common.Hash]*Transaction),
+		hashes:                 make(map[*Transaction][]common.Hash),
+		blocks:                 make(map[common.Hash]*types.Block),
+		next:                   startBlock,
+		blockReceiptsSupported: true,
+		stop:                   make(chan struct{}),
+		stopped:                make(chan struct{}),
+	}
+}
+
+// track adds hash of the tx (or of its replacement) to be matched in blocks.
+func (c *receiptCollector) track(tx *Transaction, hash common.Hash) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, exists := c.hashes[tx]; !exists {
+		c.tracked.Add(1)
+	}
+	c.hashes[tx] = append(c.hashes[tx], hash)
+	c.pending[hash] = tx
+}
+
+// setReceipt stores receipt of the tx and stops tracking all its hashes.
+func (c *receiptCollector) setReceipt(tx *Transaction, txReceipt *types.Receipt) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if tx.receipt != nil {
+		return
+	}
+	tx.receipt = txReceipt
+	c.collected.Add(1)
+
+	for _, hash := range c.hashes[tx] {
+		delete(c.pending, hash)
+	}
+	if _, exists := c.blocks[txReceipt.BlockHash]; !exists {
+		c.blocks[txReceipt.BlockHash] = nil
+	}
+}
+
+// start walks new blocks as they arrive until finish is called or ctx is canceled.
+func (c *receiptCollector) start(ctx context.Context) {
+	go func() {
+		defer close(c.stopped)
+
+		for {
+			if c.next <= c.test.heads.Current() {
+				if _, err := c.walk(ctx); err != nil {
+					fmt.Printf("live receipt collection paused: %v \n", err)
+				}
+				fmt.Printf("Test %s: block %d, mined %d/%d sent txs \n", c.test.testName, c.next-1, c.collected.Load(), c.tracked.Load())
+			}
+
+			select {
+			case <-c.stop:
+				return
+			case <-ctx.Done():
+				return
+			default:
+			}
+			c.test.heads.WaitNewHead(ctx, time.Second*time.Duration(AttemptsToCollectIntervalSec))
+		}
+	}()
+}
+
+// finish stops live collection and keeps walking blocks for the txs still in flight.
+// Walking stops when all txs are found, or when no tx was found for AttemptsToCollect * AttemptsToCollectIntervalSec.
+// Blocks with collected receipts are re-checked against the canonical chain then, txs of reorged blocks are walked again.
+func (c *receiptCollector) finish(ctx context.Context) {
+	close(c.stop)
+	<-c.stopped
+
+	// sending is over: rejected txs and replacements can't be mined, they aren't waited for
+	c.mu.Lock()
+	c.pending = make(map[common.Hash]*Transaction)
+	for tx, hashes := range c.hashes {
+		if !tx.awaitsReceipt() {
+			continue
+		}
+		for _, hash := range hashes {
+			c.pending[hash] = tx
+		}
+	}
+	c.mu.Unlock()
+
+	for ctx.Err() == nil {
+		if err := c.walkPending(ctx); err != nil {
+			fmt.Printf("failed to collect receipts of block %d, txs are left for per-tx polling: %v \n", c.next, err)
+			return
+		}
+
+		reorged, err := c.dropReorged(ctx)
+		if err != nil {
+			fmt.Printf("failed to re-check blocks of collected receipts: %v \n", err)
+			return
+		}
+		if !reorged {
+			return
+		}
+	}
+}
+
+// walkPending walks new blocks until all pending txs are found or no tx was found within the collection budget.
+func (c *receiptCollector) walkPending(ctx context.Context) error {
+	budget := time.Second * time.Duration(AttemptsToCollect*AttemptsToCollectIntervalSec)
+	deadline := time.Now().Add(budget)
+	for len(c.pending) != 0 && time.Now().Before(deadline) && ctx.Err() == nil {
+		if c.next > c.test.heads.Current() {
+			c.test.heads.WaitNewHead(ctx, min(time.Until(deadline), time.Second*time.Duration(AttemptsToCollectIntervalSec)))
+			continue
+		}
+
+		found, err := c.walk(ctx)
+		if err != nil {
+			return err
+		}
+		if found {
+			deadline = time.Now().Add(budget)
+		}
+	}
+
+	return nil
+}
+
+// dropReorged compares blocks of collected receipts with the canonical chain.
+// Receipts of replaced blocks are dropped, their txs are tracked again and walking restarts from the first replaced block.
+func (c *receiptCollector) dropReorged(ctx context.Context) (bool, error) {
+	c.mu.Lock()
+	blockHashes := make(map[uint64]map[common.Hash]bool)
+	for tx := range c.hashes {
+		if tx.receipt == nil {
+			continue
+		}
+		number := tx.receipt.BlockNumber.Uint64()
+		if blockHashes[number] == nil {
+			blockHashes[number] = make(map[common.Hash]bool)
+		}
+		blockHashes[number][tx.receipt.BlockHash] = true
+	}
+	c.mu.Unlock()
+
+	numbers := make([]uint64, 0, len(blockHashes))
+	for number := range blockHashes {
+		numbers = append(numbers, number)
+	}
+	sort.Slice(numbers, func(i, j int) bool { return numbers[i] < numbers[j] })
+
+	headers, err := c.test.fetchBlockHeaders(ctx, numbers)
+	if err != nil {
+		return false, err
+	}
+
+	reorged := make(map[common.Hash]bool)
+	for _, number := range numbers {
+		header, exists := headers[number]
+		if !exists {
+			continue
+		}
+		for hash := range blockHashes[number] {
+			if hash != header.Hash {
+				reorged[hash] = true
+				c.next = min(c.next, number)
+			}
+		}
+	}
+	if len(reorged) == 0 {
+		return false, nil
+	}
+
+	c.mu.Lock()
+	for tx, hashes := range c.hashes {
+		if tx.receipt == nil || !reorged[tx.receipt.BlockHash] {
+			continue
+		}
+		tx.receipt = nil
+		c.collected.Add(-1)
+		for _, hash := range hashes {
+			c.pending[hash] = tx
+		}
+	}
+	for hash := range reorged {
+		delete(c.blocks, hash)
+	}
+	c.mu.Unlock()
+
+	fmt.Printf("Test %s: %d blocks with collected receipts were reorged, walking again from block %d \n", c.test.testName, len(reorged), c.next)
+
+	return true, nil
+}
+
+// walk matches receipts of blocks from next up to the current head, returns whether any tx was found.
+// A block the node doesn't have yet (e.g. endpoint behind a balancer lags) is walked on the next head.
+func (c *receiptCollector) walk(ctx context.Context) (bool, error) {
+	found := false
+	for head := c.test.heads.Current(); c.next <= head && ctx.Err() == nil; c.next++ {
+		receipts, err := c.blockReceipts(ctx, c.next)
+		if errors.Is(err, ethereum.NotFound) {
+			return found, nil
+		}
+		if err != nil {
+			return found, err
+		}
+
+		for _, txReceipt := range receipts {
+			c.mu.Lock()
+			tx, exists := c.pending[txReceipt.TxHash]
+			c.mu.Unlock()
+			if exists {
+				c.setReceipt(tx, txReceipt)
+				found = true
+			}
+		}
+	}
+
+	return found, nil
+}
+
+// blockReceipts requests all receipts of the block with eth_getBlockReceipts,
+// nodes without it are asked for the block and receipts of our txs in it.
+func (c *receiptCollector) blockReceipts(ctx context.Context, number uint64) ([]*types.Receipt, error) {
+	if c.blockReceiptsSupported {
+		receipts, err := c.test.client.BlockReceipts(ctx, rpc.BlockNumberOrHashWithNumber(rpc.BlockNumber(number)))
+		if !isMethodNotFound(err) {
+			return receipts, err
+		}
+		fmt.Println("eth_getBlockReceipts is not supported by the node, receipts are requested per tx")
+		c.blockReceiptsSupported = false
+	}
+
+	block, err := c.test.client.BlockByNumber(ctx, new(big.Int).SetUint64(number))
+	if err != nil {
+		return nil, err
+	}
+
+	var receipts []*types.Receipt
+	for _, blockTx := range block.Transactions() {
+		c.mu.Lock()
+		_, exists := c.pending[blockTx.Hash()]
+		c.mu.Unlock()
+		if !exists {
+			continue
+		}
+
+		txReceipt, err := c.test.client.TransactionReceipt(ctx, blockTx.Hash())
+		if err != nil {
+			return nil, err
+		}
+		receipts = append(receipts, txReceipt)
+	}
+
+	if len(receipts) != 0 {
+		c.mu.Lock()
+		c.blocks[block.Hash()] = block
+		c.mu.Unlock()
+	}
+
+	return receipts, nil
+}
+
+// isMethodNotFound checks whether the node doesn't support the requested RPC method.
+func isMethodNotFound(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) && rpcErr.ErrorCode() == -32601 {
+		return true
+	}
+
+	message := strings.ToLower(err.Error())
+	return strings.Contains(message, "method not found") ||
+		strings.Contains(message, "does not exist") ||
+		strings.Contains(message, "not supported")
+}
+
+// awaitsReceipt checks whether the tx was sent and accepted by the node, but its receipt isn't collected yet.
+func (tx *Transaction) awaitsReceipt() bool {
+	return tx.receipt == nil && tx.sendErr == nil && tx.sentTimestamp != 0
+}
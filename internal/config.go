@@ -22,12 +22,68 @@ type Config struct {
 // AppConfig contains the main application settings.
 type AppConfig struct {
 	Node NodeConfig `yaml:"node"`
+	// ShutdownGraceSec limits receipts collection of already sent txs after interruption (Ctrl-C), 30 by default
+	ShutdownGraceSec int `yaml:"shutdown_grace_sec"`
 }
 
 // NodeConfig holds configuration details for connecting to a blockchain node.
 type NodeConfig struct {
-	RPCURL  string `yaml:"rpc_url"`
-	ChainID int64  `yaml:"chain_id"`
+	RPCURL      string            `yaml:"rpc_url"`
+	ChainID     int64             `yaml:"chain_id"`
+	Endpoints   []EndpointConfig  `yaml:"endpoints"` // endpoints to spread sending txs across, rpc_url is used when not set
+	Balancing   string            `yaml:"balancing"` // round_robin (default), weighted or sender
+	HealthCheck HealthCheckConfig `yaml:"health_check"`
+	// HeadPollIntervalMs is eth_blockNumber polling interval for HTTP endpoints, ws/ipc endpoints use newHeads subscription
+	HeadPollIntervalMs int `yaml:"head_poll_interval_ms"`
+	// connection settings, applied to rpc_url and all endpoints
+	Headers   map[string]string `yaml:"headers"` // values may reference environment variables, e.g. "${API_KEY}"
+	Auth      AuthConfig        `yaml:"auth"`
+	TLS       TLSConfig         `yaml:"tls"`
+	Transport TransportConfig   `yaml:"transport"`
+	Retry     RetryConfig       `yaml:"retry"`
+}
+
+// EndpointConfig is an RPC endpoint used for sending transactions.
+type EndpointConfig struct {
+	URL     string            `yaml:"url"`
+	Weight  int               `yaml:"weight"`  // weight for weighted balancing, 1 by default
+	Headers map[string]string `yaml:"headers"` // added to node headers, e.g. provider API key of this endpoint
+}
+
+// AuthConfig defines Authorization header of RPC requests, only one auth method can be set.
+type AuthConfig struct {
+	Bearer        string `yaml:"bearer"`          // static bearer token
+	JWTSecretFile string `yaml:"jwt_secret_file"` // hex encoded HS256 secret, like engine API, token is created per request
+	Username      string `yaml:"username"`        // basic auth
+	Password      string `yaml:"password"`
+}
+
+// TLSConfig defines TLS client certificate and trusted CA of RPC connections.
+type TLSConfig struct {
+	CertFile           string `yaml:"cert_file"`
+	KeyFile            string `yaml:"key_file"`
+	CAFile             string `yaml:"ca_file"`
+	InsecureSkipVerify bool   `yaml:"insecure_skip_verify"`
+}
+
+// RetryConfig defines retries of requests failed with transient errors (timeouts, 429, 5xx).
+type RetryConfig struct {
+	MaxRetries       int `yaml:"max_retries"`        // 3 by default, -1 disables retries
+	InitialBackoffMs int `yaml:"initial_backoff_ms"` // delay before the first retry, doubled with every retry, 100 by default
+	MaxBackoffMs     int `yaml:"max_backoff_ms"`     // 5000 by default
+}
+
+// TransportConfig tunes HTTP connections to RPC endpoints.
+type TransportConfig struct {
+	RequestTimeoutMs int `yaml:"request_timeout_ms"` // timeout of a single HTTP request, no timeout when not set
+	MaxIdleConns     int `yaml:"max_idle_conns"`     // kept-alive idle connections per host, 2 by default (Go default)
+	MaxConnsPerHost  int `yaml:"max_conns_per_host"` // no limit when not set
+}
+
+// HealthCheckConfig defines health checks of endpoints, failing endpoints are ejected from sending.
+type HealthCheckConfig struct {
+	IntervalSec int `yaml:"interval_sec"` // health checks are disabled when not set
+	MaxFailures int `yaml:"max_failures"` // consecutive failed checks to eject endpoint, 3 by default
 }
 
 // TestEntity defines configuration parameters for test scenarios.
@@ -37,33 +93,108 @@ type TestEntity struct {
 }
 
 type TestConfig struct {
-	Senders  int            `yaml:"senders"`
-	Duration int            `yaml:"duration"`
-	TPS      int            `yaml:"tps"`
-	Contract ContractConfig `yaml:"contract"`
-	DataSize int            `yaml:"data_size"`
-	value    string         `yaml:"value"`
+	Senders       int               `yaml:"senders"`
+	SenderIndices []int             `yaml:"sender_indices"` // explicit indices of senders, used instead of senders count
+	SenderRange   string            `yaml:"sender_range"`   // ranges of sender indices, e.g. "0-3,7"
+	SenderGroup   string            `yaml:"sender_group"`   // name of a group from senders.groups
+	Duration      int               `yaml:"duration"`
+	TPS           int               `yaml:"tps"`
+	Contract      ContractConfig    `yaml:"contract"`
+	DataSize      int               `yaml:"data_size"`
+	Value         string            `yaml:"value"` // wei
+	Receivers     ReceiversConfig   `yaml:"receivers"`
+	Payload       PayloadConfig     `yaml:"payload"`
+	Gas           GasConfig         `yaml:"gas"`
+	Replacement   ReplacementConfig `yaml:"replacement"`
+	Batch         BatchConfig       `yaml:"batch"`
+	Call          CallConfig        `yaml:"call"`
+}
+
+// CallConfig defines eth_call requests of call tests.
+type CallConfig struct {
+	FromSender bool `yaml:"from_sender"` // set call From to the sender, calls are sent without From by default
+}
+
+// BatchConfig defines grouping of sends, calls and receipt requests into JSON-RPC batches.
+type BatchConfig struct {
+	Size            int `yaml:"size"`              // requests per batch, batching is disabled when not set
+	FlushIntervalMs int `yaml:"flush_interval_ms"` // max time a request waits for the batch to fill, 50 by default
+}
+
+// ReplacementConfig defines re-sending of txs with the same nonce and bumped fee (replace-by-fee).
+type ReplacementConfig struct {
+	Fraction       float64 `yaml:"fraction"`         // share of txs to replace, from 0 to 1
+	DelayMs        int     `yaml:"delay_ms"`         // delay between the original tx and its replacement
+	FeeBumpPercent int     `yaml:"fee_bump_percent"` // fee cap and tip increase, 10 by default
+}
+
+// GasConfig defines gas limit and fees of test transactions.
+type GasConfig struct {
+	Limit         uint64    `yaml:"limit"`          // fixed gas limit, estimated when not set
+	Estimate      string    `yaml:"estimate"`       // per_tx (default) or once (estimate once per function and cache)
+	FallbackLimit uint64    `yaml:"fallback_limit"` // gas limit used when estimation fails
+	FeeCap        FeeConfig `yaml:"fee_cap"`
+	Tip           FeeConfig `yaml:"tip"`
+}
+
+// FeeConfig defines how fee cap (maxFeePerGas) or tip (maxPriorityFeePerGas) is calculated.
+type FeeConfig struct {
+	Mode       string  `yaml:"mode"`       // suggested (default), multiplier or fixed for fee cap; suggested, fixed or percentile for tip
+	Multiplier float64 `yaml:"multiplier"` // base fee multiplier for fee cap, 2 by default
+	Gwei       string  `yaml:"gwei"`       // fixed value in gwei
+	Percentile float64 `yaml:"percentile"` // eth_feeHistory reward percentile for tip
+	Blocks     uint64  `yaml:"blocks"`     // eth_feeHistory block count for tip, 20 by default
+}
+
+// PayloadConfig defines how data of transactions without contract is generated.
+type PayloadConfig struct {
+	Mode      string  `yaml:"mode"`       // repeat (default), random, zero, ratio or hex
+	ZeroRatio float64 `yaml:"zero_ratio"` // share of zero bytes for ratio mode, from 0 to 1
+	Hex       string  `yaml:"hex"`        // fixed payload for hex mode
+	MinSize   int     `yaml:"min_size"`   // random size range in bytes, data_size is used when not set
+	MaxSize   int     `yaml:"max_size"`
+}
+
+// ReceiversConfig defines receivers of plain transfers (sends without contract).
+type ReceiversConfig struct {
+	Mode      string   `yaml:"mode"`      // self (default), senders, list or random
+	Addresses []string `yaml:"addresses"` // receivers for list mode
 }
 
 // ContractConfig contains configs for contract testing
 type ContractConfig struct {
-	Address  string         `yaml:"address"`
-	Function FunctionConfig `yaml:"function"`
+	Address   string           `yaml:"address"`
+	Function  FunctionConfig   `yaml:"function"`
+	Functions []FunctionConfig `yaml:"functions"` // weighted mix of functions, used instead of function
 }
 
 // FunctionConfig contains details about a smart contract function, including ABI and parameters.
-// todo: add random function data
-// todo: add function data from file
+// todo: params are the same in every tx, random params aren't supported yet (payload modes apply to plain transfers only)
 type FunctionConfig struct {
-	Name   string        `yaml:"name"`
-	ABI    string        `yaml:"abi"`
-	Params []interface{} `yaml:"params"`
+	Name    string        `yaml:"name"`     // function name or signature for overloaded functions, e.g. transfer(address,uint256)
+	ABI     string        `yaml:"abi"`      // inline ABI json
+	ABIFile string        `yaml:"abi_file"` // plain ABI json or Hardhat/Foundry/Truffle build artifact, used instead of abi
+	Params  []interface{} `yaml:"params"`
+	Weight  int           `yaml:"weight"`  // weight in the functions mix, 1 by default
+	Address string        `yaml:"address"` // overrides contract address for this function
+	// expected return values of the function in call tests, same format as params, results are only decoded when not set
+	Expected []interface{} `yaml:"expected"`
 }
 
 // SendersConfig stores sender-related configurations, including private keys.
 // todo - add private keys from file and passphrase
 type SendersConfig struct {
-	PrivateKeys []string `yaml:"private_keys"`
+	PrivateKeys   []string          `yaml:"private_keys"`
+	Addresses     []string          `yaml:"addresses"` // senders signed by the remote signer, indexed after private keys
+	Signer        SignerConfig      `yaml:"signer"`
+	Groups        map[string]string `yaml:"groups"`         // named groups of sender indices, e.g. "0-3,7"
+	DisjointPools bool              `yaml:"disjoint_pools"` // tests don't share senders, count based tests get unused senders
+}
+
+// SignerConfig defines the remote signer (Clef, Web3Signer) used for senders.addresses.
+type SignerConfig struct {
+	URL    string `yaml:"url"`
+	Method string `yaml:"method"` // eth_signTransaction (default, geth/Web3Signer) or account_signTransaction (Clef)
 }
 
 // LoadConfig loads config yaml file in Config
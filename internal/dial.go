@@ -0,0 +1,191 @@
+package internal
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/gorilla/websocket"
+)
+
+const JWTSecretLength = 32
+
+// DialNode connects to the node RPC endpoint with auth, headers, TLS, transport and retry settings of the node config.
+func DialNode(url string, config NodeConfig) (*RpcClient, error) {
+	options, err := dialOptions(config)
+	if err != nil {
+		return nil, fmt.Errorf("invalid connection settings for %s: %w", url, err)
+	}
+
+	rpcClient, err := rpc.DialOptions(context.Background(), url, options...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to establish connection with Ethereum node %s: %w", url, err)
+	}
+
+	return NewRpcClient(ethclient.NewClient(rpcClient), url, config.Retry), nil
+}
+
+func dialOptions(config NodeConfig) ([]rpc.ClientOption, error) {
+	var options []rpc.ClientOption
+
+	// header values may reference environment variables, e.g. "${RPC_API_KEY}"
+	headers := http.Header{}
+	for key, value := range config.Headers {
+		headers.Set(key, os.ExpandEnv(value))
+	}
+	options = append(options, rpc.WithHeaders(headers))
+
+	auth, err := newHTTPAuth(config.Auth)
+	if err != nil {
+		return nil, err
+	}
+	if auth != nil {
+		options = append(options, rpc.WithHTTPAuth(auth))
+	}
+
+	tlsConfig, err := loadTLSConfig(config.TLS)
+	if err != nil {
+		return nil, err
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	if config.Transport.MaxIdleConns > 0 {
+		transport.MaxIdleConns = config.Transport.MaxIdleConns
+		transport.MaxIdleConnsPerHost = config.Transport.MaxIdleConns
+	}
+	if config.Transport.MaxConnsPerHost > 0 {
+		transport.MaxConnsPerHost = config.Transport.MaxConnsPerHost
+	}
+	options = append(options, rpc.WithHTTPClient(&http.Client{
+		Transport: transport,
+		Timeout:   time.Duration(config.Transport.RequestTimeoutMs) * time.Millisecond,
+	}))
+
+	if tlsConfig != nil {
+		options = append(options, rpc.WithWebsocketDialer(websocket.Dialer{
+			Proxy:            http.ProxyFromEnvironment,
+			HandshakeTimeout: 45 * time.Second,
+			TLSClientConfig:  tlsConfig,
+		}))
+	}
+
+	return options, nil
+}
+
+// newHTTPAuth returns the provider of Authorization header, nil when auth isn't configured.
+func newHTTPAuth(config AuthConfig) (rpc.HTTPAuth, error) {
+	configured := 0
+	for _, isSet := range []bool{config.Bearer != "", config.JWTSecretFile != "", config.Username != ""} {
+		if isSet {
+			configured++
+		}
+	}
+	if configured > 1 {
+		return nil, fmt.Errorf("only one of auth bearer, jwt_secret_file or username/password can be set")
+	}
+
+	switch {
+	case config.Bearer != "":
+		token := os.ExpandEnv(config.Bearer)
+		return func(h http.Header) error {
+			h.Set("Authorization", "Bearer "+token)
+			return nil
+		}, nil
+
+	case config.Username != "":
+		credentials := base64.StdEncoding.EncodeToString([]byte(os.ExpandEnv(config.Username) + ":" + os.ExpandEnv(config.Password)))
+		return func(h http.Header) error {
+			h.Set("Authorization", "Basic "+credentials)
+			return nil
+		}, nil
+
+	case config.JWTSecretFile != "":
+		secret, err := loadJWTSecret(config.JWTSecretFile)
+		if err != nil {
+			return nil, err
+		}
+		// token is short-lived (engine API accepts iat within 60 seconds), a fresh one is created for every request
+		return func(h http.Header) error {
+			h.Set("Authorization", "Bearer "+newJWTToken(secret, time.Now()))
+			return nil
+		}, nil
+	}
+
+	return nil, nil
+}
+
+// loadJWTSecret reads hex encoded 32 byte secret, same format as geth --authrpc.jwtsecret.
+func loadJWTSecret(file string) ([]byte, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JWT secret file '%s': %w", file, err)
+	}
+
+	str := strings.TrimSpace(string(data))
+	str = strings.TrimPrefix(strings.TrimPrefix(str, "0x"), "0X")
+	secret, err := hex.DecodeString(str)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JWT secret in '%s': %w", file, err)
+	}
+	if len(secret) != JWTSecretLength {
+		return nil, fmt.Errorf("invalid JWT secret in '%s': expected %d bytes, got %d", file, JWTSecretLength, len(secret))
+	}
+
+	return secret, nil
+}
+
+// newJWTToken creates HS256 signed JWT with the iat claim.
+func newJWTToken(secret []byte, issuedAt time.Time) string {
+	header, _ := json.Marshal(map[string]string{"alg": "HS256", "typ": "JWT"})
+	claims, _ := json.Marshal(map[string]int64{"iat": issuedAt.Unix()})
+
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(unsigned))
+
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// loadTLSConfig loads client certificate and CA, nil is returned when TLS settings aren't configured.
+func loadTLSConfig(config TLSConfig) (*tls.Config, error) {
+	if config.CertFile == "" && config.KeyFile == "" && config.CAFile == "" && !config.InsecureSkipVerify {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{InsecureSkipVerify: config.InsecureSkipVerify}
+
+	if config.CertFile != "" || config.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(config.CertFile, config.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load TLS client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	if config.CAFile != "" {
+		data, err := os.ReadFile(config.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read TLS CA file '%s': %w", config.CAFile, err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("no certificates found in TLS CA file '%s'", config.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	return tlsConfig, nil
+}
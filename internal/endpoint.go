@@ -0,0 +1,244 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	BalancingRoundRobin = "round_robin" // endpoints take turns
+	BalancingWeighted   = "weighted"    // random endpoint according to weights
+	BalancingSender     = "sender"      // each sender is pinned to one endpoint
+
+	DefaultHealthCheckMaxFailures = 3
+	HealthCheckTimeoutSec         = 5
+)
+
+// Endpoint is an RPC endpoint used for sending transactions.
+type Endpoint struct {
+	url      string
+	weight   int
+	client   *RpcClient
+	healthy  atomic.Bool
+	failures atomic.Int32 // consecutive failed health checks
+}
+
+// EndpointPool spreads eth_sendRawTransaction across several RPC endpoints.
+// Endpoints failing health checks are ejected from the pool until they recover.
+type EndpointPool struct {
+	endpoints   []*Endpoint
+	balancing   string
+	totalWeight int
+	next        atomic.Uint64
+	healthCheck HealthCheckConfig
+	stop        chan struct{}
+	wg          sync.WaitGroup
+}
+
+// EndpointMetrics contains send and inclusion metrics of txs sent through an endpoint.
+type EndpointMetrics struct {
+	url              string
+	sentTxs          uint
+	sendErrors       uint
+	minedTxs         uint
+	avgRpcLatency    float64 // in ms
+	avgTimeToInclude uint    // in ms
+	healthy          bool
+}
+
+// NewEndpointPool dials configured endpoints, the primary client is the only endpoint when none are configured.
+func NewEndpointPool(config NodeConfig, primary *RpcClient) (*EndpointPool, error) {
+	pool := &EndpointPool{
+		balancing:   config.Balancing,
+		healthCheck: config.HealthCheck,
+		stop:        make(chan struct{}),
+	}
+
+	switch pool.balancing {
+	case "":
+		pool.balancing = BalancingRoundRobin
+	case BalancingRoundRobin, BalancingWeighted, BalancingSender:
+	default:
+		return nil, fmt.Errorf("unknown balancing: %s (expected %s, %s or %s)", config.Balancing, BalancingRoundRobin, BalancingWeighted, BalancingSender)
+	}
+
+	if pool.healthCheck.MaxFailures <= 0 {
+		pool.healthCheck.MaxFailures = DefaultHealthCheckMaxFailures
+	}
+
+	if len(config.Endpoints) == 0 {
+		pool.add(&Endpoint{url: config.RPCURL, weight: 1, client: primary})
+		return pool, nil
+	}
+
+	for _, endpointConfig := range config.Endpoints {
+		client := primary
+		if endpointConfig.URL != config.RPCURL || len(endpointConfig.Headers) != 0 {
+			endpointNode := config
+			endpointNode.Headers = make(map[string]string)
+			for key, value := range config.Headers {
+				endpointNode.Headers[key] = value
+			}
+			for key, value := range endpointConfig.Headers {
+				endpointNode.Headers[key] = value
+			}
+
+			var err error
+			client, err = DialNode(endpointConfig.URL, endpointNode)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		weight := endpointConfig.Weight
+		if weight <= 0 {
+			weight = 1
+		}
+		pool.add(&Endpoint{url: endpointConfig.URL, weight: weight, client: client})
+	}
+
+	return pool, nil
+}
+
+func (p *EndpointPool) add(endpoint *Endpoint) {
+	endpoint.healthy.Store(true)
+	p.endpoints = append(p.endpoints, endpoint)
+	p.totalWeight += endpoint.weight
+}
+
+// pick returns an endpoint for the next tx of the sender according to the balancing mode.
+func (p *EndpointPool) pick(sender *Sender) *Endpoint {
+	if len(p.endpoints) == 1 {
+		return p.endpoints[0]
+	}
+
+	healthy := make([]*Endpoint, 0, len(p.endpoints))
+	healthyWeight := 0
+	for _, endpoint := range p.endpoints {
+		if endpoint.healthy.Load() {
+			healthy = append(healthy, endpoint)
+			healthyWeight += endpoint.weight
+		}
+	}
+	// all endpoints are ejected, keep sending rather than stop the test
+	if len(healthy) == 0 {
+		healthy = p.endpoints
+		healthyWeight = p.totalWeight
+	}
+
+	switch p.balancing {
+	case BalancingWeighted:
+		n := rand.Intn(healthyWeight)
+		for _, endpoint := range healthy {
+			if n < endpoint.weight {
+				return endpoint
+			}
+			n -= endpoint.weight
+		}
+		return healthy[len(healthy)-1]
+	case BalancingSender:
+		hash := fnv.New32a()
+		hash.Write(sender.Address.Bytes())
+		pinned := p.endpoints[int(hash.Sum32()%uint32(len(p.endpoints)))]
+		if pinned.healthy.Load() {
+			return pinned
+		}
+		return healthy[int(hash.Sum32()%uint32(len(healthy)))]
+	default:
+		return healthy[int(p.next.Add(1)-1)%len(healthy)]
+	}
+}
+
+// StartHealthChecks periodically checks endpoints with eth_blockNumber, ejects failing and re-admits recovered endpoints.
+func (p *EndpointPool) StartHealthChecks() {
+	if p.healthCheck.IntervalSec <= 0 || len(p.endpoints) == 1 {
+		return
+	}
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		ticker := time.NewTicker(time.Duration(p.healthCheck.IntervalSec) * time.Second)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-p.stop:
+				return
+			case <-ticker.C:
+				for _, endpoint := range p.endpoints {
+					p.checkEndpoint(endpoint)
+				}
+			}
+		}
+	}()
+}
+
+// StopHealthChecks stops health checks started by StartHealthChecks.
+func (p *EndpointPool) StopHealthChecks() {
+	close(p.stop)
+	p.wg.Wait()
+}
+
+func (p *EndpointPool) checkEndpoint(endpoint *Endpoint) {
+	ctx, cancel := context.WithTimeout(context.Background(), HealthCheckTimeoutSec*time.Second)
+	defer cancel()
+
+	// single request without retries, a failed check counts towards ejection
+	_, err := endpoint.client.Client.BlockNumber(ctx)
+	if err == nil {
+		endpoint.failures.Store(0)
+		if !endpoint.healthy.Swap(true) {
+			fmt.Printf("Endpoint %s recovered, returned to the pool\n", endpoint.url)
+		}
+		return
+	}
+
+	if endpoint.failures.Add(1) >= int32(p.healthCheck.MaxFailures) && endpoint.healthy.Swap(false) {
+		fmt.Printf("Endpoint %s ejected from the pool: %v\n", endpoint.url, err)
+	}
+}
+
+// collectEndpointMetrics breaks down send errors, RPC latency and inclusion latency by endpoint.
+func (t *Test) collectEndpointMetrics(blockTimes map[uint64]uint64) []*EndpointMetrics {
+	endpointsMetrics := make([]*EndpointMetrics, 0, len(t.endpoints.endpoints))
+	for _, endpoint := range t.endpoints.endpoints {
+		metrics := &EndpointMetrics{url: endpoint.url, healthy: endpoint.healthy.Load()}
+		var rpcLatency time.Duration
+		var timeToInclude int64
+
+		for _, senderTxs := range t.senderTransactions {
+			for _, tx := range senderTxs {
+				if tx.endpoint != endpoint {
+					continue
+				}
+
+				metrics.sentTxs++
+				rpcLatency += tx.sendLatency
+				if tx.sendFailed() {
+					metrics.sendErrors++
+				}
+				if txTimeToInclude, ok := tx.timeToInclude(blockTimes); ok {
+					metrics.minedTxs++
+					timeToInclude += txTimeToInclude
+				}
+			}
+		}
+
+		if metrics.sentTxs != 0 {
+			metrics.avgRpcLatency = float64(rpcLatency.Microseconds()) / 1000 / float64(metrics.sentTxs)
+		}
+		if metrics.minedTxs != 0 {
+			metrics.avgTimeToInclude = uint(timeToInclude / int64(metrics.minedTxs))
+		}
+
+		endpointsMetrics = append(endpointsMetrics, metrics)
+	}
+
+	return endpointsMetrics
+}
@@ -0,0 +1,160 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Send error categories of eth_sendRawTransaction.
+const (
+	SendErrorNonceTooLow            = "nonce too low"
+	SendErrorAlreadyKnown           = "already known"
+	SendErrorReplacementUnderpriced = "replacement underpriced"
+	SendErrorFeeTooLow              = "fee too low"
+	SendErrorTxPoolFull             = "txpool full"
+	SendErrorInsufficientFunds      = "insufficient funds"
+	SendErrorIntrinsicGasTooLow     = "intrinsic gas too low"
+	SendErrorTimeout                = "timeout"
+	SendErrorOther                  = "other"
+)
+
+// SendErrorCategories lists send error categories in the report order.
+var SendErrorCategories = []string{
+	SendErrorNonceTooLow,
+	SendErrorAlreadyKnown,
+	SendErrorReplacementUnderpriced,
+	SendErrorFeeTooLow,
+	SendErrorTxPoolFull,
+	SendErrorInsufficientFunds,
+	SendErrorIntrinsicGasTooLow,
+	SendErrorTimeout,
+	SendErrorOther,
+}
+
+// SendErrors counts send errors of a test by category and keeps the first seen message of each category.
+type SendErrors struct {
+	mu        sync.Mutex
+	counts    map[string]uint
+	firstSeen map[string]string
+}
+
+// SendErrorMetrics contains count and the first seen message of a send error category.
+type SendErrorMetrics struct {
+	category  string
+	count     uint
+	firstSeen string
+}
+
+func NewSendErrors() *SendErrors {
+	return &SendErrors{counts: make(map[string]uint), firstSeen: make(map[string]string)}
+}
+
+// add records the send error, returns its category.
+func (e *SendErrors) add(err error) string {
+	category := classifySendError(err)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.counts[category]++
+	if _, exists := e.firstSeen[category]; !exists {
+		e.firstSeen[category] = err.Error()
+	}
+
+	return category
+}
+
+// metrics returns categories with errors in the report order.
+func (e *SendErrors) metrics() []*SendErrorMetrics {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var metrics []*SendErrorMetrics
+	for _, category := range SendErrorCategories {
+		if e.counts[category] == 0 {
+			continue
+		}
+		metrics = append(metrics, &SendErrorMetrics{category: category, count: e.counts[category], firstSeen: e.firstSeen[category]})
+	}
+
+	return metrics
+}
+
+// classifySendError maps error of tx sending to one of the send error categories.
+// Clients use different messages, so matching is done by known message fragments.
+func classifySendError(err error) string {
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return SendErrorTimeout
+	}
+
+	message := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(message, "nonce too low"):
+		return SendErrorNonceTooLow
+	case strings.Contains(message, "already known"),
+		strings.Contains(message, "known transaction"),
+		strings.Contains(message, "already imported"):
+		return SendErrorAlreadyKnown
+	case strings.Contains(message, "replacement transaction underpriced"),
+		strings.Contains(message, "replacement underpriced"):
+		return SendErrorReplacementUnderpriced
+	// fee below the tx pool minimum or the block base fee
+	case strings.Contains(message, "underpriced"),
+		strings.Contains(message, "fee too low"),
+		strings.Contains(message, "less than block base fee"):
+		return SendErrorFeeTooLow
+	case strings.Contains(message, "txpool is full"),
+		strings.Contains(message, "transaction pool is full"),
+		strings.Contains(message, "txpool full"):
+		return SendErrorTxPoolFull
+	case strings.Contains(message, "insufficient funds"):
+		return SendErrorInsufficientFunds
+	case strings.Contains(message, "intrinsic gas too low"):
+		return SendErrorIntrinsicGasTooLow
+	case strings.Contains(message, "timeout"),
+		strings.Contains(message, "timed out"),
+		strings.Contains(message, "deadline exceeded"):
+		return SendErrorTimeout
+	default:
+		return SendErrorOther
+	}
+}
+
+// isRetryableError reports whether the request may succeed if sent again:
+// timeouts, dropped connections, rate limiting (429) and server errors (5xx).
+func isRetryableError(err error) bool {
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode == http.StatusTooManyRequests || httpErr.StatusCode >= http.StatusInternalServerError
+	}
+
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return true
+	}
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.EPIPE) {
+		return true
+	}
+
+	// JSON-RPC "limit exceeded" (EIP-1474) and rate limit messages of providers
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) && rpcErr.ErrorCode() == -32005 {
+		return true
+	}
+
+	message := strings.ToLower(err.Error())
+	return strings.Contains(message, "too many requests") ||
+		strings.Contains(message, "rate limit") ||
+		strings.Contains(message, "connection reset") ||
+		strings.Contains(message, "timed out")
+}
@@ -0,0 +1,50 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+)
+
+func TestClassifySendError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"nonce too low", errors.New("nonce too low: next nonce 5, tx nonce 3"), SendErrorNonceTooLow},
+		{"already known", errors.New("already known"), SendErrorAlreadyKnown},
+		{"known transaction", errors.New("Known transaction: 0xabc"), SendErrorAlreadyKnown},
+		{"already imported", errors.New("Transaction with the same hash was already imported."), SendErrorAlreadyKnown},
+		{"replacement underpriced", errors.New("replacement transaction underpriced"), SendErrorReplacementUnderpriced},
+		{"replacement underpriced short", errors.New("ReplacementUnderpriced: replacement underpriced"), SendErrorReplacementUnderpriced},
+		{"transaction underpriced", errors.New("transaction underpriced: tip needed 1, tip permitted 0"), SendErrorFeeTooLow},
+		{"fee too low", errors.New("FeeTooLow: fee too low, minimum is 1 gwei"), SendErrorFeeTooLow},
+		{"below base fee", errors.New("max fee per gas less than block base fee: address 0x1, maxFeePerGas: 1, baseFee: 7"), SendErrorFeeTooLow},
+		{"txpool is full", errors.New("txpool is full"), SendErrorTxPoolFull},
+		{"transaction pool is full", errors.New("transaction pool is full"), SendErrorTxPoolFull},
+		{"insufficient funds", errors.New("insufficient funds for gas * price + value"), SendErrorInsufficientFunds},
+		{"intrinsic gas", errors.New("intrinsic gas too low: have 20000, want 21000"), SendErrorIntrinsicGasTooLow},
+		{"deadline exceeded", fmt.Errorf("send failed: %w", context.DeadlineExceeded), SendErrorTimeout},
+		{"net timeout", &net.OpError{Op: "read", Net: "tcp", Err: timeoutError{}}, SendErrorTimeout},
+		{"timeout message", errors.New("request timed out"), SendErrorTimeout},
+		{"other", errors.New("execution reverted"), SendErrorOther},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := classifySendError(test.err); got != test.want {
+				t.Fatalf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+// timeoutError is a net error reporting a timeout.
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
@@ -0,0 +1,176 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// invalid call results are grouped in the report by these errors, the decoded values aren't part of the group
+var (
+	UndecodableResult = errors.New("failed to decode result")
+	UnexpectedResult  = errors.New("unexpected result")
+)
+
+// Contract holds the weighted mix of contract functions used by a test.
+type Contract struct {
+	functions   []*Function
+	totalWeight int
+}
+
+type Function struct {
+	label   string
+	address string
+	name    string
+	abi     string
+	abiFile string
+	params  []interface{} `yaml:"params"`
+	weight  int
+	// expected call result, compared with decoded result in call tests when set
+	expectedParams []interface{}
+	// prepared data
+	data     []byte
+	method   abi.Method
+	expected []interface{}
+}
+
+// NewContract builds contract functions mix from the test config.
+// Single `function` is used as the mix of one function when `functions` are not defined.
+// A function without address, name or ABI is a config error, the test isn't turned into plain transfers.
+func NewContract(config ContractConfig) (Contract, error) {
+	functionConfigs := config.Functions
+	if len(functionConfigs) == 0 && (config.Function.Name != "" || config.Function.ABI != "" || config.Function.ABIFile != "") {
+		functionConfigs = []FunctionConfig{config.Function}
+	}
+
+	contract := Contract{}
+	labels := make(map[string]int)
+	for _, functionConfig := range functionConfigs {
+		function := &Function{
+			label:   functionConfig.Name,
+			address: functionConfig.Address,
+			name:    functionConfig.Name,
+			abi:     functionConfig.ABI,
+			abiFile: functionConfig.ABIFile,
+			params:  functionConfig.Params,
+			weight:  functionConfig.Weight,
+
+			expectedParams: functionConfig.Expected,
+		}
+
+		if function.address == "" {
+			function.address = config.Address
+		}
+		if function.weight <= 0 {
+			function.weight = 1
+		}
+		if function.address == "" || function.name == "" || (function.abi == "" && function.abiFile == "") {
+			return Contract{}, fmt.Errorf("contract function #%d '%s': address, name and abi (or abi_file) are required", len(contract.functions), function.name)
+		}
+
+		// same function may be used several times with different params
+		labels[function.label]++
+		if labels[function.label] > 1 {
+			function.label = fmt.Sprintf("%s#%d", function.label, labels[function.label])
+		}
+
+		contract.functions = append(contract.functions, function)
+		contract.totalWeight += function.weight
+	}
+
+	return contract, nil
+}
+
+// isDefined checks that the test uses contract functions, functions are validated by NewContract.
+func (c *Contract) isDefined() bool {
+	return len(c.functions) != 0
+}
+
+// prepare packs call data for every function in the mix.
+func (c *Contract) prepare() error {
+	for _, function := range c.functions {
+		if function.data != nil {
+			continue
+		}
+
+		data, err := function.packData()
+		if err != nil {
+			return fmt.Errorf("function %s: %w", function.label, err)
+		}
+		function.data = data
+	}
+
+	return nil
+}
+
+// pick returns a random function from the mix according to the weights.
+func (c *Contract) pick() *Function {
+	if len(c.functions) == 1 {
+		return c.functions[0]
+	}
+
+	n := rand.Intn(c.totalWeight)
+	for _, function := range c.functions {
+		if n < function.weight {
+			return function
+		}
+		n -= function.weight
+	}
+
+	return c.functions[len(c.functions)-1]
+}
+
+func (f *Function) contractAddress() common.Address {
+	return common.HexToAddress(f.address)
+}
+
+// packData loads function ABI, selects the method and packs it with converted params into tx data.
+func (f *Function) packData() ([]byte, error) {
+	parsedABI, err := loadABI(f.abi, f.abiFile)
+	if err != nil {
+		return nil, err
+	}
+
+	abiMethod, err := findMethod(parsedABI, f.name)
+	if err != nil {
+		return nil, err
+	}
+
+	convertedParams, err := convertParams(abiMethod.Inputs, f.params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert function parameters: %w", err)
+	}
+
+	data, err := parsedABI.Pack(abiMethod.Name, convertedParams...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack ABI data: %w", err)
+	}
+
+	if f.expectedParams != nil {
+		f.expected, err = convertParams(abiMethod.Outputs, f.expectedParams)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert expected result: %w", err)
+		}
+	}
+	f.method = abiMethod
+
+	return data, nil
+}
+
+// checkResult decodes the call result with the function ABI and compares it with the expected values, when they're set.
+// Values are compared by their printed form, equal big.Int values may differ in internal representation.
+func (f *Function) checkResult(result []byte) error {
+	values, err := f.method.Outputs.Unpack(result)
+	if err != nil {
+		return fmt.Errorf("%w: %w", UndecodableResult, err)
+	}
+
+	if f.expected != nil && fmt.Sprint(values) != fmt.Sprint(f.expected) {
+		return fmt.Errorf("%w: got %v, expected %v", UnexpectedResult, values, f.expected)
+	}
+
+	return nil
+}
@@ -0,0 +1,210 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+const (
+	GasEstimatePerTx = "per_tx" // estimate gas limit for every tx
+	GasEstimateOnce  = "once"   // estimate gas limit once per function (or plain transfer) and cache it
+
+	FeeSuggested  = "suggested"  // eth_gasPrice for fee cap, eth_maxPriorityFeePerGas for tip
+	FeeMultiplier = "multiplier" // fee cap = base fee * multiplier + tip
+	FeeFixed      = "fixed"      // fixed value in gwei
+	FeePercentile = "percentile" // tip as percentile of rewards from eth_feeHistory
+
+	DefaultFeeHistoryBlocks = 20
+	TransferGasKey          = "transfer" // gas estimation cache key of plain transfers
+)
+
+// GasStrategy defines gas limit and fees of test transactions.
+// Fees are requested once per test, gas limit is fixed, estimated per tx or estimated once and cached.
+type GasStrategy struct {
+	config    GasConfig
+	tipCap    *big.Int
+	feeCap    *big.Int
+	estimates map[string]gasEstimate
+}
+
+// gasEstimate is a cached gas limit along with calldata gas of the estimated tx,
+// so the limit can be adjusted for txs with other data (e.g. random payloads).
+type gasEstimate struct {
+	gas     uint64
+	dataGas uint64
+}
+
+func NewGasStrategy(config GasConfig) *GasStrategy {
+	return &GasStrategy{
+		config:    config,
+		estimates: make(map[string]gasEstimate),
+	}
+}
+
+// prepareFees requests fee data from the node according to the fee config.
+func (g *GasStrategy) prepareFees(ctx context.Context, client *RpcClient) error {
+	var err error
+
+	switch g.config.Tip.Mode {
+	case "", FeeSuggested:
+		g.tipCap, err = client.SuggestGasTipCap(ctx)
+		if err != nil {
+			return fmt.Errorf("error getting tipCap: %w", err)
+		}
+	case FeeFixed:
+		g.tipCap, err = parseGwei(g.config.Tip.Gwei)
+		if err != nil {
+			return fmt.Errorf("invalid tip: %w", err)
+		}
+	case FeePercentile:
+		g.tipCap, err = g.percentileTip(ctx, client)
+		if err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown tip mode: %s (expected %s, %s or %s)", g.config.Tip.Mode, FeeSuggested, FeeFixed, FeePercentile)
+	}
+
+	switch g.config.FeeCap.Mode {
+	case "", FeeSuggested:
+		g.feeCap, err = client.SuggestGasPrice(ctx)
+		if err != nil {
+			return fmt.Errorf("error getting feeCap: %w", err)
+		}
+	case FeeFixed:
+		g.feeCap, err = parseGwei(g.config.FeeCap.Gwei)
+		if err != nil {
+			return fmt.Errorf("invalid fee cap: %w", err)
+		}
+	case FeeMultiplier:
+		header, err := client.HeaderByNumber(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("error getting latest header: %w", err)
+		}
+		if header.BaseFee == nil {
+			return fmt.Errorf("fee cap mode '%s' requires London fork (latest block has no base fee)", FeeMultiplier)
+		}
+
+		multiplier := g.config.FeeCap.Multiplier
+		if multiplier <= 0 {
+			multiplier = 2
+		}
+		feeCap, _ := new(big.Float).Mul(new(big.Float).SetInt(header.BaseFee), big.NewFloat(multiplier)).Int(nil)
+		g.feeCap = feeCap.Add(feeCap, g.tipCap)
+	default:
+		return fmt.Errorf("unknown fee cap mode: %s (expected %s, %s or %s)", g.config.FeeCap.Mode, FeeSuggested, FeeMultiplier, FeeFixed)
+	}
+
+	// node rejects txs with tip higher than fee cap
+	if g.feeCap.Cmp(g.tipCap) < 0 {
+		g.feeCap = new(big.Int).Set(g.tipCap)
+	}
+
+	return nil
+}
+
+// percentileTip returns median of reward percentiles over the recent blocks.
+func (g *GasStrategy) percentileTip(ctx context.Context, client *RpcClient) (*big.Int, error) {
+	blocks := g.config.Tip.Blocks
+	if blocks == 0 {
+		blocks = DefaultFeeHistoryBlocks
+	}
+
+	feeHistory, err := client.FeeHistory(ctx, blocks, nil, []float64{g.config.Tip.Percentile})
+	if err != nil {
+		return nil, fmt.Errorf("error getting fee history: %w", err)
+	}
+
+	var rewards []*big.Int
+	for _, blockRewards := range feeHistory.Reward {
+		if len(blockRewards) != 0 && blockRewards[0] != nil {
+			rewards = append(rewards, blockRewards[0])
+		}
+	}
+
+	if len(rewards) == 0 {
+		tipCap, err := client.SuggestGasTipCap(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error getting tipCap: %w", err)
+		}
+		return tipCap, nil
+	}
+
+	sort.Slice(rewards, func(i, j int) bool {
+		return rewards[i].Cmp(rewards[j]) < 0
+	})
+
+	return new(big.Int).Set(rewards[len(rewards)/2]), nil
+}
+
+// fees returns tip and fee cap of test transactions.
+func (g *GasStrategy) fees() (*big.Int, *big.Int) {
+	return g.tipCap, g.feeCap
+}
+
+// gasLimit returns gas limit for the tx, key groups txs with the same execution (function label or transfer).
+func (g *GasStrategy) gasLimit(ctx context.Context, client *RpcClient, key string, msg ethereum.CallMsg) (uint64, error) {
+	if g.config.Limit != 0 {
+		return g.config.Limit, nil
+	}
+
+	switch g.config.Estimate {
+	case "", GasEstimatePerTx:
+		return g.estimateGas(ctx, client, msg)
+	case GasEstimateOnce:
+		if estimate, exists := g.estimates[key]; exists {
+			return estimate.gas - estimate.dataGas + calldataGas(msg.Data), nil
+		}
+
+		gas, err := g.estimateGas(ctx, client, msg)
+		if err != nil {
+			return 0, err
+		}
+		g.estimates[key] = gasEstimate{gas: gas, dataGas: calldataGas(msg.Data)}
+		return gas, nil
+	default:
+		return 0, fmt.Errorf("unknown gas estimate mode: %s (expected %s or %s)", g.config.Estimate, GasEstimatePerTx, GasEstimateOnce)
+	}
+}
+
+func (g *GasStrategy) estimateGas(ctx context.Context, client *RpcClient, msg ethereum.CallMsg) (uint64, error) {
+	gas, err := client.EstimateGas(ctx, msg)
+	if err != nil {
+		if g.config.FallbackLimit != 0 {
+			return g.config.FallbackLimit, nil
+		}
+		return 0, fmt.Errorf("failed to estimate gas: %w", err)
+	}
+
+	return gas, nil
+}
+
+// calldataGas returns intrinsic gas cost of tx data (EIP-2028).
+func calldataGas(data []byte) uint64 {
+	var gas uint64
+	for _, b := range data {
+		if b == 0 {
+			gas += params.TxDataZeroGas
+		} else {
+			gas += params.TxDataNonZeroGasEIP2028
+		}
+	}
+
+	return gas
+}
+
+// parseGwei converts decimal (possibly fractional) gwei string into wei.
+func parseGwei(gwei string) (*big.Int, error) {
+	value, ok := new(big.Float).SetPrec(256).SetString(gwei)
+	if !ok || value.Sign() < 0 {
+		return nil, fmt.Errorf("invalid gwei value: %q", gwei)
+	}
+
+	wei, _ := value.Mul(value, big.NewFloat(params.GWei)).Int(nil)
+	return wei, nil
+}
@@ -0,0 +1,144 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const DefaultHeadPollIntervalMs = 500
+
+// HeadTracker keeps the latest block number, so senders don't request it before every tx.
+// Heads come from newHeads subscription (ws/ipc endpoints), HTTP endpoints are polled with eth_blockNumber.
+type HeadTracker struct {
+	client       *RpcClient
+	pollInterval time.Duration
+	number       atomic.Uint64
+	// newHead is closed and replaced on every new head to wake up all waiters
+	newHead chan struct{}
+	mu      sync.Mutex
+	stop    chan struct{}
+	wg      sync.WaitGroup
+}
+
+func NewHeadTracker(client *RpcClient, pollIntervalMs int) *HeadTracker {
+	if pollIntervalMs <= 0 {
+		pollIntervalMs = DefaultHeadPollIntervalMs
+	}
+
+	return &HeadTracker{
+		client:       client,
+		pollInterval: time.Duration(pollIntervalMs) * time.Millisecond,
+		newHead:      make(chan struct{}),
+		stop:         make(chan struct{}),
+	}
+}
+
+// Start requests the current head and starts following new heads.
+func (h *HeadTracker) Start() error {
+	number, err := h.client.BlockNumber(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to get block number: %w", err)
+	}
+	h.setHead(number)
+
+	h.wg.Add(1)
+	go func() {
+		defer h.wg.Done()
+		if !h.subscribe() {
+			h.poll()
+		}
+	}()
+
+	return nil
+}
+
+// Stop stops following heads.
+func (h *HeadTracker) Stop() {
+	close(h.stop)
+	h.wg.Wait()
+}
+
+// Current returns the latest known block number.
+func (h *HeadTracker) Current() uint64 {
+	return h.number.Load()
+}
+
+// WaitNewHead blocks until the next head arrives or timeout expires, returns false on timeout or ctx cancellation.
+func (h *HeadTracker) WaitNewHead(ctx context.Context, timeout time.Duration) bool {
+	h.mu.Lock()
+	newHead := h.newHead
+	h.mu.Unlock()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-newHead:
+		return true
+	case <-timer.C:
+		return false
+	case <-h.stop:
+		return false
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// subscribe follows heads by newHeads subscription, returns false when subscriptions are not supported
+// or the subscription failed, so the tracker should fall back to polling.
+func (h *HeadTracker) subscribe() bool {
+	headers := make(chan *types.Header)
+	subscription, err := h.client.SubscribeNewHead(context.Background(), headers)
+	if err != nil {
+		return false
+	}
+	defer subscription.Unsubscribe()
+
+	for {
+		select {
+		case <-h.stop:
+			return true
+		case header := <-headers:
+			h.setHead(header.Number.Uint64())
+		case err = <-subscription.Err():
+			fmt.Printf("newHeads subscription failed, falling back to polling: %v\n", err)
+			return false
+		}
+	}
+}
+
+// poll follows heads by eth_blockNumber requests.
+func (h *HeadTracker) poll() {
+	ticker := time.NewTicker(h.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-h.stop:
+			return
+		case <-ticker.C:
+			number, err := h.client.BlockNumber(context.Background())
+			if err != nil {
+				continue
+			}
+			h.setHead(number)
+		}
+	}
+}
+
+func (h *HeadTracker) setHead(number uint64) {
+	if number <= h.number.Load() {
+		return
+	}
+	h.number.Store(number)
+
+	h.mu.Lock()
+	close(h.newHead)
+	h.newHead = make(chan struct{})
+	h.mu.Unlock()
+}
@@ -0,0 +1,144 @@
+package internal
+
+import (
+	"math"
+	"math/bits"
+)
+
+// HistogramSubBucketBits defines precision of the histogram: every power of two range of values
+// is split into 2^(HistogramSubBucketBits-1) linear sub-buckets, values below 2^HistogramSubBucketBits are exact.
+const HistogramSubBucketBits = 5
+
+// Histogram records non-negative values (ms, blocks) in log-linear buckets, like HdrHistogram:
+// memory doesn't grow with the number of values and percentiles are bounded: a percentile is the highest value
+// of its bucket, so it overestimates by less than the sub-bucket width, 1/2^(HistogramSubBucketBits-1) (6.25%).
+type Histogram struct {
+	counts []uint64
+	total  uint64
+	sum    int64
+	min    int64
+	max    int64
+}
+
+// HistogramBucket is a range of values with the number of recorded values in it.
+type HistogramBucket struct {
+	low   int64
+	high  int64
+	count uint64
+}
+
+func NewHistogram() *Histogram {
+	return &Histogram{min: math.MaxInt64}
+}
+
+// record adds the value, negative values are recorded as 0.
+func (h *Histogram) record(value int64) {
+	value = max(value, 0)
+
+	index := histogramIndex(value)
+	if index >= len(h.counts) {
+		counts := make([]uint64, index+1)
+		copy(counts, h.counts)
+		h.counts = counts
+	}
+
+	h.counts[index]++
+	h.total++
+	h.sum += value
+	h.min = min(h.min, value)
+	h.max = max(h.max, value)
+}
+
+// percentile returns the value below or equal to which p percent of values fall (highest value of its bucket).
+func (h *Histogram) percentile(p float64) int64 {
+	if h.total == 0 {
+		return 0
+	}
+
+	rank := uint64(math.Ceil(p / 100 * float64(h.total)))
+	rank = max(rank, 1)
+
+	var count uint64
+	for index, bucketCount := range h.counts {
+		count += bucketCount
+		if count >= rank {
+			_, high := histogramBucketRange(index)
+			return min(high, h.max)
+		}
+	}
+
+	return h.max
+}
+
+func (h *Histogram) mean() float64 {
+	if h.total == 0 {
+		return 0
+	}
+	return float64(h.sum) / float64(h.total)
+}
+
+func (h *Histogram) minValue() int64 {
+	if h.total == 0 {
+		return 0
+	}
+	return h.min
+}
+
+// buckets returns non-empty buckets of the full histogram precision.
+func (h *Histogram) buckets() []HistogramBucket {
+	var buckets []HistogramBucket
+	for index, count := range h.counts {
+		if count == 0 {
+			continue
+		}
+		low, high := histogramBucketRange(index)
+		buckets = append(buckets, HistogramBucket{low: low, high: high, count: count})
+	}
+	return buckets
+}
+
+// powerOfTwoBuckets merges buckets into power of two ranges (0, 1, 2-3, 4-7, ...) for compact table output.
+func (h *Histogram) powerOfTwoBuckets() []HistogramBucket {
+	var buckets []HistogramBucket
+	for _, bucket := range h.buckets() {
+		low := int64(0)
+		if bucket.low > 0 {
+			low = int64(1) << (bits.Len64(uint64(bucket.low)) - 1)
+		}
+
+		if len(buckets) != 0 && buckets[len(buckets)-1].low == low {
+			buckets[len(buckets)-1].count += bucket.count
+			continue
+		}
+
+		high := int64(0)
+		if low > 0 {
+			high = low<<1 - 1
+		}
+		buckets = append(buckets, HistogramBucket{low: low, high: high, count: bucket.count})
+	}
+	return buckets
+}
+
+// histogramIndex returns bucket index of the value: values below 2^HistogramSubBucketBits have own buckets,
+// larger values are grouped by exponent and the top HistogramSubBucketBits bits.
+func histogramIndex(value int64) int {
+	if value < 1<<HistogramSubBucketBits {
+		return int(value)
+	}
+
+	exponent := bits.Len64(uint64(value)) - HistogramSubBucketBits
+	subBucket := int(value >> exponent)
+	return exponent<<(HistogramSubBucketBits-1) + subBucket
+}
+
+// histogramBucketRange returns the lowest and the highest value of the bucket.
+func histogramBucketRange(index int) (int64, int64) {
+	if index < 1<<HistogramSubBucketBits {
+		return int64(index), int64(index)
+	}
+
+	exponent := index>>(HistogramSubBucketBits-1) - 1
+	subBucket := int64(index - exponent<<(HistogramSubBucketBits-1))
+	return subBucket << exponent, (subBucket+1)<<exponent - 1
+}
@@ -0,0 +1,60 @@
+package internal
+
+import "testing"
+
+func TestHistogramPercentile(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []int64
+		p      float64
+		want   int64
+	}{
+		{"empty", nil, 50, 0},
+		{"single value", []int64{1000}, 99, 1000},
+		{"exact below 32", []int64{1, 2, 3, 31}, 75, 3},
+		{"lowest percentile", []int64{5, 10, 20}, 0, 5},
+		{"highest percentile", []int64{5, 10, 20}, 100, 20},
+		{"negative recorded as 0", []int64{-5, 10}, 50, 0},
+		{"bucket high", []int64{100, 1000}, 50, 103},
+		{"capped at max", []int64{1000, 1001}, 100, 1001},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			histogram := NewHistogram()
+			for _, value := range test.values {
+				histogram.record(value)
+			}
+			if got := histogram.percentile(test.p); got != test.want {
+				t.Fatalf("got %d, want %d", got, test.want)
+			}
+		})
+	}
+}
+
+func TestHistogramPercentileBound(t *testing.T) {
+	const maxValue = int64(1) << 40
+
+	for value := int64(0); value < 1<<20; value = value*17/16 + 1 {
+		histogram := NewHistogram()
+		histogram.record(value)
+		histogram.record(maxValue)
+
+		got := histogram.percentile(50)
+		if value < 1<<HistogramSubBucketBits && got != value {
+			t.Fatalf("value %d: got %d, expected exact value", value, got)
+		}
+		if got < value || float64(got) > float64(value)*1.0625 {
+			t.Fatalf("value %d: got %d, out of 6.25%% bound", value, got)
+		}
+	}
+}
+
+func TestHistogramBucketRange(t *testing.T) {
+	for _, value := range []int64{0, 1, 31, 32, 33, 63, 64, 100, 1000, 123456789} {
+		low, high := histogramBucketRange(histogramIndex(value))
+		if value < low || value > high {
+			t.Fatalf("value %d is out of its bucket %d-%d", value, low, high)
+		}
+	}
+}
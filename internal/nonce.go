@@ -0,0 +1,112 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+)
+
+// handleSendError records the send error of the tx and marks sender for nonce re-sync,
+// as a tx rejected by the node leaves a nonce gap that blocks all later txs of the sender.
+func (t *Test) handleSendError(sender *Sender, tx *Transaction, err error) {
+	category := t.sendErrors.add(err)
+	switch category {
+	case SendErrorAlreadyKnown:
+		// tx is already in the tx pool (e.g. sent again after timeout)
+		return
+	case SendErrorTimeout:
+		// node may have accepted the tx before the timeout, it's still collected and counted as failed only if not mined
+		tx.sendTimeout = err
+	default:
+		tx.sendErr = err
+	}
+
+	sender.needsResync.Store(true)
+	fmt.Printf("failed to send transaction (%s): %v \n", category, err)
+}
+
+// resyncSender re-syncs sender nonce with the node pending nonce and re-signs the remaining queue of txs,
+// so they follow the last accepted tx without gaps.
+// Nonces above the pending one taken by sent txs accepted after the failed one (batched sends) are skipped.
+func (t *Test) resyncSender(ctx context.Context, sender *Sender, sent []*Transaction, queue []*Transaction) error {
+	nonce, err := t.client.PendingNonceAt(ctx, *sender.Address)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve nonce for address %s: %w", sender.Address.Hex(), err)
+	}
+
+	taken := make(map[uint64]bool)
+	t.collector.mu.Lock()
+	for _, tx := range sent {
+		if tx.sendErr == nil && tx.clientTransaction.Nonce() > nonce {
+			taken[tx.clientTransaction.Nonce()] = true
+		}
+	}
+	t.collector.mu.Unlock()
+
+	changed := false
+	for _, tx := range queue {
+		for taken[nonce] {
+			nonce++
+		}
+
+		clientTx := tx.clientTransaction
+		if clientTx.Nonce() != nonce {
+			resignedTx, err := resignTransaction(t.chainId, sender, clientTx, nonce, clientTx.GasTipCap(), clientTx.GasFeeCap())
+			if err != nil {
+				return err
+			}
+			tx.clientTransaction = resignedTx
+
+			if tx.replacement != nil {
+				tx.replacement, err = t.signReplacement(sender, resignedTx)
+				if err != nil {
+					return err
+				}
+			}
+			changed = true
+		}
+		nonce++
+	}
+	for taken[nonce] {
+		nonce++
+	}
+
+	if changed {
+		atomic.AddInt32(&t.resyncs, 1)
+		sender.nonceChanged = true
+	}
+
+	sender.Nonce = nonce
+	sender.needsResync.Store(false)
+
+	return nil
+}
+
+// markGapLostTxs finds sent but not mined txs stuck behind a nonce gap.
+// Node pending nonce stops at the first gap, so txs with nonce above it can't be mined.
+func (t *Test) markGapLostTxs(ctx context.Context) {
+	for _, sender := range t.senders {
+		var pendingNonce uint64
+		nonceFetched := false
+
+		for _, tx := range t.senderTransactions[sender.Address.String()] {
+			if tx.receipt != nil || tx.sendFailed() || tx.sentTimestamp == 0 {
+				continue
+			}
+
+			if !nonceFetched {
+				nonce, err := t.client.PendingNonceAt(ctx, *sender.Address)
+				if err != nil {
+					fmt.Printf("failed to retrieve nonce for address %s: %v \n", sender.Address.Hex(), err)
+					break
+				}
+				pendingNonce = nonce
+				nonceFetched = true
+			}
+
+			if tx.clientTransaction.Nonce() >= pendingNonce {
+				tx.gapLost = true
+			}
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package internal
+
+import (
+	"crypto/rand"
+	"fmt"
+	mathrand "math/rand"
+)
+
+const (
+	PayloadRepeat = "repeat" // repeated 'A' bytes
+	PayloadRandom = "random" // random bytes
+	PayloadZero   = "zero"   // zero bytes
+	PayloadRatio  = "ratio"  // zero and random non-zero bytes in configured ratio
+	PayloadHex    = "hex"    // fixed hex string
+)
+
+// payloadGenerator creates data of transactions without contract.
+type payloadGenerator struct {
+	mode      string
+	zeroRatio float64
+	minSize   int
+	maxSize   int
+	// fixed is reused for every tx when payload doesn't change between txs
+	fixed []byte
+}
+
+func newPayloadGenerator(config PayloadConfig, dataSize int) (*payloadGenerator, error) {
+	generator := &payloadGenerator{
+		mode:      config.Mode,
+		zeroRatio: config.ZeroRatio,
+		minSize:   dataSize,
+		maxSize:   dataSize,
+	}
+
+	if config.MinSize != 0 || config.MaxSize != 0 {
+		if config.MinSize < 0 || config.MaxSize < config.MinSize {
+			return nil, fmt.Errorf("invalid payload size range: min_size %d, max_size %d", config.MinSize, config.MaxSize)
+		}
+		generator.minSize = config.MinSize
+		generator.maxSize = config.MaxSize
+	}
+
+	switch config.Mode {
+	case "", PayloadRepeat:
+		generator.mode = PayloadRepeat
+	case PayloadRandom, PayloadZero:
+	case PayloadRatio:
+		if config.ZeroRatio < 0 || config.ZeroRatio > 1 {
+			return nil, fmt.Errorf("invalid payload zero_ratio: %v (expected value from 0 to 1)", config.ZeroRatio)
+		}
+	case PayloadHex:
+		if config.MinSize != 0 || config.MaxSize != 0 {
+			return nil, fmt.Errorf("payload min_size and max_size don't apply to %s mode, size is defined by hex", PayloadHex)
+		}
+		data, err := parseHexBytes(config.Hex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid payload hex: %w", err)
+		}
+		generator.fixed = data
+	default:
+		return nil, fmt.Errorf("unknown payload mode: %s (expected %s, %s, %s, %s or %s)",
+			config.Mode, PayloadRepeat, PayloadRandom, PayloadZero, PayloadRatio, PayloadHex)
+	}
+
+	// payload of fixed size without randomness is the same for every tx
+	if generator.minSize == generator.maxSize && (generator.mode == PayloadRepeat || generator.mode == PayloadZero) {
+		generator.fixed = generator.generate(generator.minSize)
+	}
+
+	return generator, nil
+}
+
+// next returns data for the next tx, nil means tx without data.
+func (g *payloadGenerator) next() ([]byte, error) {
+	if g.fixed != nil {
+		return g.fixed, nil
+	}
+
+	size := g.minSize
+	if g.maxSize > g.minSize {
+		size += mathrand.Intn(g.maxSize - g.minSize + 1)
+	}
+
+	if g.mode == PayloadRandom || g.mode == PayloadRatio {
+		data := make([]byte, size)
+		if _, err := rand.Read(data); err != nil {
+			return nil, fmt.Errorf("failed to generate random payload: %w", err)
+		}
+		if g.mode == PayloadRatio {
+			g.applyZeroRatio(data)
+		}
+		return data, nil
+	}
+
+	return g.generate(size), nil
+}
+
+// generate creates payload of repeat or zero mode.
+func (g *payloadGenerator) generate(size int) []byte {
+	if size == 0 {
+		return nil
+	}
+
+	data := make([]byte, size)
+	if g.mode == PayloadRepeat {
+		for i := range data {
+			data[i] = 0x41 // 'A'
+		}
+	}
+
+	return data
+}
+
+// applyZeroRatio makes exactly zeroRatio share of bytes zero, the rest of bytes are non-zero.
+func (g *payloadGenerator) applyZeroRatio(data []byte) {
+	for i := range data {
+		if data[i] == 0 {
+			data[i] = byte(1 + mathrand.Intn(255))
+		}
+	}
+
+	zeroBytes := int(float64(len(data))*g.zeroRatio + 0.5)
+	for _, i := range mathrand.Perm(len(data))[:zeroBytes] {
+		data[i] = 0
+	}
+}
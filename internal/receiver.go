@@ -0,0 +1,74 @@
+package internal
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	ReceiverSelf    = "self"    // sender sends to itself
+	ReceiverSenders = "senders" // round-robin among test senders
+	ReceiverList    = "list"    // round-robin among configured addresses
+	ReceiverRandom  = "random"  // fresh random address for each tx
+)
+
+// receiverPicker chooses receivers of plain transfers (non-contract sends).
+type receiverPicker struct {
+	mode      string
+	addresses []common.Address
+	next      int
+}
+
+func newReceiverPicker(config ReceiversConfig, senders []*Sender, value *big.Int) (*receiverPicker, error) {
+	picker := &receiverPicker{mode: config.Mode}
+
+	switch config.Mode {
+	case "", ReceiverSelf:
+		picker.mode = ReceiverSelf
+	case ReceiverSenders:
+		for _, sender := range senders {
+			picker.addresses = append(picker.addresses, *sender.Address)
+		}
+	case ReceiverList:
+		if len(config.Addresses) == 0 {
+			return nil, fmt.Errorf("receivers mode '%s' requires at least one address", ReceiverList)
+		}
+		for _, address := range config.Addresses {
+			if !common.IsHexAddress(address) {
+				return nil, fmt.Errorf("invalid receiver address: %s", address)
+			}
+			picker.addresses = append(picker.addresses, common.HexToAddress(address))
+		}
+	case ReceiverRandom:
+		// EIP-161: zero value transfer to an empty address doesn't create the account, there is no state growth
+		if value.Sign() == 0 {
+			return nil, fmt.Errorf("receivers mode '%s' requires non-zero value, zero value transfers don't create accounts", ReceiverRandom)
+		}
+	default:
+		return nil, fmt.Errorf("unknown receivers mode: %s (expected %s, %s, %s or %s)",
+			config.Mode, ReceiverSelf, ReceiverSenders, ReceiverList, ReceiverRandom)
+	}
+
+	return picker, nil
+}
+
+// pick returns receiver of the next tx from sender.
+func (p *receiverPicker) pick(sender *Sender) (common.Address, error) {
+	switch p.mode {
+	case ReceiverSenders, ReceiverList:
+		receiver := p.addresses[p.next%len(p.addresses)]
+		p.next++
+		return receiver, nil
+	case ReceiverRandom:
+		var receiver common.Address
+		if _, err := rand.Read(receiver[:]); err != nil {
+			return common.Address{}, fmt.Errorf("failed to generate random receiver: %w", err)
+		}
+		return receiver, nil
+	default:
+		return *sender.Address, nil
+	}
+}
@@ -0,0 +1,152 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const DefaultFeeBumpPercent = 10 // minimal fee bump accepted by geth tx pool for replacement
+
+// Replacement is a tx re-sent with the same nonce and bumped fee after the original tx.
+type Replacement struct {
+	clientTransaction *types.Transaction
+	sent              bool
+	sentTimestamp     int64
+	sendErr           error
+	sendLatency       time.Duration
+}
+
+// ReplacementMetrics contains results of tx replacements (replace-by-fee).
+type ReplacementMetrics struct {
+	sentReplacements     uint
+	acceptedReplacements uint
+	rejectedReplacements uint
+	minedOriginal        uint // txs with accepted replacement, where the original tx was mined
+	minedReplacement     uint
+	notMined             uint
+	avgTimeToInclude     uint // in ms, of txs with accepted replacement from the original tx send
+	latencyPenalty       int  // in ms, avg time to include of replaced txs minus avg time to include of other txs
+}
+
+// shouldReplace randomly chooses txs for replacement according to the configured fraction.
+func (t *Test) shouldReplace() bool {
+	return t.replacement.Fraction > 0 && rand.Float64() < t.replacement.Fraction
+}
+
+// signReplacement signs a copy of tx with the same nonce and fees bumped by the configured percent.
+func (t *Test) signReplacement(sender *Sender, tx *types.Transaction) (*Replacement, error) {
+	bumpPercent := t.replacement.FeeBumpPercent
+	if bumpPercent <= 0 {
+		bumpPercent = DefaultFeeBumpPercent
+	}
+
+	tipCap := bumpFee(tx.GasTipCap(), bumpPercent)
+	feeCap := bumpFee(tx.GasFeeCap(), bumpPercent)
+
+	replacementTx, err := resignTransaction(t.chainId, sender, tx, tx.Nonce(), tipCap, feeCap)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign replacement: %w", err)
+	}
+
+	return &Replacement{clientTransaction: replacementTx}, nil
+}
+
+// sendReplacement sends replacement of the tx after the configured delay, replacement isn't sent when ctx is canceled meanwhile.
+func (t *Test) sendReplacement(ctx context.Context, wg *sync.WaitGroup, tx *Transaction) {
+	defer wg.Done()
+
+	timer := time.NewTimer(time.Duration(t.replacement.DelayMs) * time.Millisecond)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+		return
+	}
+
+	tx.replacement.sentTimestamp = time.Now().UnixMilli()
+	t.collector.track(tx, tx.replacement.clientTransaction.Hash())
+	tx.replacement.sendLatency, tx.replacement.sendErr = tx.endpoint.client.sendTransactionTimed(context.WithoutCancel(ctx), tx.replacement.clientTransaction)
+	tx.replacement.sent = true
+	if tx.replacement.sendErr != nil {
+		t.sendErrors.add(tx.replacement.sendErr)
+		fmt.Printf("replacement rejected: txHash=%s, error: %v \n", tx.replacement.clientTransaction.Hash(), tx.replacement.sendErr)
+	}
+}
+
+// isReplaced checks whether replacement of the tx was accepted by the node.
+func (tx *Transaction) isReplaced() bool {
+	return tx.replacement != nil && tx.replacement.sent && tx.replacement.sendErr == nil
+}
+
+// hashes returns hashes of the tx and its accepted replacement, any of them may be mined.
+func (tx *Transaction) hashes() []common.Hash {
+	hashes := []common.Hash{tx.clientTransaction.Hash()}
+	if tx.isReplaced() {
+		hashes = append(hashes, tx.replacement.clientTransaction.Hash())
+	}
+
+	return hashes
+}
+
+// collectReplacementMetrics counts accepted/rejected replacements, which version was mined and the latency penalty.
+func (t *Test) collectReplacementMetrics(blockTimes map[uint64]uint64) *ReplacementMetrics {
+	metrics := &ReplacementMetrics{}
+	var replacedTime, replacedCount, otherTime, otherCount int64
+
+	for _, senderTxs := range t.senderTransactions {
+		for _, tx := range senderTxs {
+			if tx.replacement == nil || !tx.replacement.sent {
+				if timeToInclude, ok := tx.timeToInclude(blockTimes); ok {
+					otherTime += timeToInclude
+					otherCount++
+				}
+				continue
+			}
+
+			metrics.sentReplacements++
+			if tx.replacement.sendErr != nil {
+				metrics.rejectedReplacements++
+				continue
+			}
+			metrics.acceptedReplacements++
+
+			switch {
+			case tx.receipt == nil:
+				metrics.notMined++
+			case tx.receipt.TxHash == tx.replacement.clientTransaction.Hash():
+				metrics.minedReplacement++
+			default:
+				metrics.minedOriginal++
+			}
+
+			if timeToInclude, ok := tx.timeToInclude(blockTimes); ok {
+				replacedTime += timeToInclude
+				replacedCount++
+			}
+		}
+	}
+
+	if replacedCount != 0 {
+		metrics.avgTimeToInclude = uint(replacedTime / replacedCount)
+		metrics.latencyPenalty = int(replacedTime / replacedCount)
+		if otherCount != 0 {
+			metrics.latencyPenalty -= int(otherTime / otherCount)
+		}
+	}
+
+	return metrics
+}
+
+// bumpFee increases fee by percent, rounding up.
+func bumpFee(fee *big.Int, percent int) *big.Int {
+	bumped := new(big.Int).Mul(fee, big.NewInt(int64(100+percent)))
+	bumped.Add(bumped, big.NewInt(99))
+	return bumped.Div(bumped, big.NewInt(100))
+}
@@ -0,0 +1,309 @@
+package internal
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+const (
+	ReportSuffix       = "_report.json"
+	BlockSeriesSuffix  = "_blocks.csv"
+	BlockDetailsSuffix = "_block_details.csv"
+	SecondSeriesSuffix = "_seconds.csv"
+)
+
+// ReportPercentiles are percentiles of latency distributions shown in the output and in the report.
+var ReportPercentiles = []float64{50, 90, 95, 99}
+
+// SendReport is the machine-readable result of a send test, written next to the text output.
+type SendReport struct {
+	Test           string            `json:"test"`
+	Type           string            `json:"type"`
+	Senders        int               `json:"senders"`
+	StartBlock     uint64            `json:"start_block"`
+	EndBlock       uint64            `json:"end_block"`
+	ConfigTps      uint              `json:"config_tps"`
+	SentTps        float64           `json:"sent_tps"`
+	MinedTps       float64           `json:"mined_tps"`
+	MgasPerSec     float64           `json:"mgas_per_sec"`
+	AvgTxsPerBlock uint              `json:"avg_txs_per_block"`
+	SucceedTxs     uint              `json:"succeed_txs"`
+	FailedTxs      uint              `json:"failed_txs"`
+	SendFailedTxs  uint              `json:"send_failed_txs"`
+	GapLostTxs     uint              `json:"gap_lost_txs"`
+	NotMinedTxs    uint              `json:"not_mined_txs"`
+	NotSentTxs     uint              `json:"not_sent_txs"`
+	TimeToInclude  HistogramReport   `json:"time_to_include_ms"`
+	BlockDistance  HistogramReport   `json:"block_distance"`
+	SendLatency    HistogramReport   `json:"send_latency_us"`
+	SendErrors     []SendErrorReport `json:"send_errors"`
+	PerSender      []SenderReport    `json:"per_sender"`
+	// Jain's fairness index of senders: 1 - all senders are treated equally, lower - values of senders spread
+	MinedShareFairness float64 `json:"mined_share_fairness"`
+	MineTimeFairness   float64 `json:"mine_time_fairness"`
+	// lowest / highest mined share (0 - a sender is starved) and fastest / slowest mean mine time of senders
+	MinedShareSpread float64                  `json:"mined_share_min_max"`
+	MineTimeSpread   float64                  `json:"mine_time_min_max"`
+	Blocks           []BlockThroughputReport  `json:"blocks"`
+	BlockDetails     []BlockDetailsReport     `json:"block_details"`
+	Seconds          []SecondThroughputReport `json:"seconds"`
+}
+
+type SenderReport struct {
+	Address       string          `json:"address"`
+	SentTxs       uint            `json:"sent_txs"`
+	MinedTxs      uint            `json:"mined_txs"`
+	FailedTxs     uint            `json:"failed_txs"`
+	SendFailedTxs uint            `json:"send_failed_txs"`
+	DroppedTxs    uint            `json:"dropped_txs"`
+	FirstNonce    uint64          `json:"first_nonce"`
+	LastNonce     uint64          `json:"last_nonce"`
+	TimeToInclude HistogramReport `json:"time_to_include_ms"`
+}
+
+type SendErrorReport struct {
+	Category  string `json:"category"`
+	Count     uint   `json:"count"`
+	FirstSeen string `json:"first_seen"`
+}
+
+// BlockThroughputReport is a row of the per-block series, sent txs are counted by the head block at send time.
+type BlockThroughputReport struct {
+	Number    uint64 `json:"number"`
+	Timestamp uint64 `json:"timestamp"`
+	SentTxs   uint   `json:"sent_txs"`
+	MinedTxs  uint   `json:"mined_txs"`
+	GasUsed   uint64 `json:"gas_used"`
+}
+
+// BlockDetailsReport describes how a block of the test window was filled, time_delta is -1 for the first block.
+type BlockDetailsReport struct {
+	Number       uint64              `json:"number"`
+	Timestamp    uint64              `json:"timestamp"`
+	TimeDelta    int64               `json:"time_delta"`
+	Txs          int                 `json:"txs"`
+	TestTxs      int                 `json:"test_txs"`
+	GasUsed      uint64              `json:"gas_used"`
+	GasLimit     uint64              `json:"gas_limit"`
+	Utilization  float64             `json:"utilization"`
+	BaseFee      *big.Int            `json:"base_fee"`
+	SenderNonces []SenderNonceReport `json:"sender_nonces"`
+}
+
+type SenderNonceReport struct {
+	Sender   string `json:"sender"`
+	MinNonce uint64 `json:"min_nonce"`
+	MaxNonce uint64 `json:"max_nonce"`
+}
+
+// SecondThroughputReport is a row of the per-second series, mined txs are counted by the block timestamp.
+type SecondThroughputReport struct {
+	Second   int64  `json:"second"`
+	SentTxs  uint   `json:"sent_txs"`
+	MinedTxs uint   `json:"mined_txs"`
+	GasUsed  uint64 `json:"gas_used"`
+}
+
+// HistogramReport contains summary and full precision buckets of a histogram.
+type HistogramReport struct {
+	Count       uint64                  `json:"count"`
+	Min         int64                   `json:"min"`
+	Max         int64                   `json:"max"`
+	Mean        float64                 `json:"mean"`
+	Percentiles map[string]int64        `json:"percentiles"`
+	Buckets     []HistogramBucketReport `json:"buckets"`
+}
+
+type HistogramBucketReport struct {
+	Low   int64  `json:"low"`
+	High  int64  `json:"high"`
+	Count uint64 `json:"count"`
+}
+
+func newHistogramReport(histogram *Histogram) HistogramReport {
+	report := HistogramReport{
+		Count:       histogram.total,
+		Min:         histogram.minValue(),
+		Max:         histogram.max,
+		Mean:        histogram.mean(),
+		Percentiles: make(map[string]int64, len(ReportPercentiles)),
+		Buckets:     []HistogramBucketReport{},
+	}
+	for _, percentile := range ReportPercentiles {
+		report.Percentiles[fmt.Sprintf("p%g", percentile)] = histogram.percentile(percentile)
+	}
+	for _, bucket := range histogram.buckets() {
+		report.Buckets = append(report.Buckets, HistogramBucketReport{Low: bucket.low, High: bucket.high, Count: bucket.count})
+	}
+
+	return report
+}
+
+func newSendReport(test Test) *SendReport {
+	report := &SendReport{
+		Test:           test.testName,
+		Type:           test.testType,
+		Senders:        len(test.senders),
+		StartBlock:     test.startBlock,
+		EndBlock:       test.endBlock,
+		ConfigTps:      test.metrics.configTps,
+		SentTps:        test.metrics.sentTps,
+		MinedTps:       test.metrics.realTps,
+		MgasPerSec:     test.metrics.mgasPerSec,
+		AvgTxsPerBlock: test.metrics.avgTxsPerBlock,
+		SucceedTxs:     test.metrics.succeedTxs,
+		FailedTxs:      test.metrics.failedTxs,
+		SendFailedTxs:  test.metrics.sendFailedTxs,
+		GapLostTxs:     test.metrics.gapLostTxs,
+		NotMinedTxs:    test.metrics.notMinedTxs,
+		NotSentTxs:     test.metrics.notSentTxs,
+		TimeToInclude:  newHistogramReport(test.metrics.timeToInclude),
+		BlockDistance:  newHistogramReport(test.metrics.blockDistance),
+		SendLatency:    newHistogramReport(test.metrics.sendLatency),
+		SendErrors:     []SendErrorReport{},
+		PerSender:      []SenderReport{},
+
+		MinedShareFairness: test.metrics.minedShareFairness,
+		MineTimeFairness:   test.metrics.mineTimeFairness,
+		MinedShareSpread:   test.metrics.minedShareSpread,
+		MineTimeSpread:     test.metrics.mineTimeSpread,
+		Blocks:             []BlockThroughputReport{},
+		Seconds:            []SecondThroughputReport{},
+	}
+	for _, sender := range test.metrics.senders {
+		report.PerSender = append(report.PerSender, SenderReport{
+			Address:       sender.address,
+			SentTxs:       sender.sentTxs,
+			MinedTxs:      sender.minedTxs,
+			FailedTxs:     sender.failedTxs,
+			SendFailedTxs: sender.sendFailedTxs,
+			DroppedTxs:    sender.droppedTxs,
+			FirstNonce:    sender.firstNonce,
+			LastNonce:     sender.lastNonce,
+			TimeToInclude: newHistogramReport(sender.timeToInclude),
+		})
+	}
+	for _, sendError := range test.metrics.sendErrors {
+		report.SendErrors = append(report.SendErrors, SendErrorReport{Category: sendError.category, Count: sendError.count, FirstSeen: sendError.firstSeen})
+	}
+	for _, block := range test.metrics.blockSeries {
+		report.Blocks = append(report.Blocks, BlockThroughputReport{
+			Number:    block.number,
+			Timestamp: block.timestamp,
+			SentTxs:   block.sentTxs,
+			MinedTxs:  block.minedTxs,
+			GasUsed:   block.gasUsed,
+		})
+	}
+	for _, block := range test.metrics.blocks {
+		blockDetails := BlockDetailsReport{
+			Number:       block.number,
+			Timestamp:    block.timestamp,
+			TimeDelta:    block.timeDelta,
+			Txs:          block.txs,
+			TestTxs:      block.testTxs,
+			GasUsed:      block.gasUsed,
+			GasLimit:     block.gasLimit,
+			Utilization:  block.utilization(),
+			BaseFee:      block.baseFee,
+			SenderNonces: []SenderNonceReport{},
+		}
+		for _, nonceRange := range block.senderNonces {
+			blockDetails.SenderNonces = append(blockDetails.SenderNonces, SenderNonceReport{
+				Sender:   nonceRange.sender,
+				MinNonce: nonceRange.minNonce,
+				MaxNonce: nonceRange.maxNonce,
+			})
+		}
+		report.BlockDetails = append(report.BlockDetails, blockDetails)
+	}
+	for _, second := range test.metrics.secondSeries {
+		report.Seconds = append(report.Seconds, SecondThroughputReport{
+			Second:   second.second,
+			SentTxs:  second.sentTxs,
+			MinedTxs: second.minedTxs,
+			GasUsed:  second.gasUsed,
+		})
+	}
+
+	return report
+}
+
+// writeReport writes json report and csv time series of the test into the test logs folder.
+func (r *Runner) writeReport(folderPath string, test Test) {
+	report := newSendReport(test)
+
+	blockRows := [][]string{{"block", "timestamp", "sent_txs", "mined_txs", "gas_used"}}
+	for _, block := range report.Blocks {
+		blockRows = append(blockRows, []string{
+			strconv.FormatUint(block.Number, 10),
+			strconv.FormatUint(block.Timestamp, 10),
+			strconv.FormatUint(uint64(block.SentTxs), 10),
+			strconv.FormatUint(uint64(block.MinedTxs), 10),
+			strconv.FormatUint(block.GasUsed, 10),
+		})
+	}
+	writeCSV(filepath.Join(folderPath, test.testType+BlockSeriesSuffix), blockRows)
+
+	blockDetailsRows := [][]string{{"block", "timestamp", "time_delta", "txs", "test_txs", "gas_used", "gas_limit", "utilization", "base_fee", "sender_nonces"}}
+	for _, block := range test.metrics.blocks {
+		baseFee := ""
+		if block.baseFee != nil {
+			baseFee = block.baseFee.String()
+		}
+		blockDetailsRows = append(blockDetailsRows, []string{
+			strconv.FormatUint(block.number, 10),
+			strconv.FormatUint(block.timestamp, 10),
+			strconv.FormatInt(block.timeDelta, 10),
+			strconv.Itoa(block.txs),
+			strconv.Itoa(block.testTxs),
+			strconv.FormatUint(block.gasUsed, 10),
+			strconv.FormatUint(block.gasLimit, 10),
+			strconv.FormatFloat(block.utilization(), 'f', 2, 64),
+			baseFee,
+			block.senderNoncesString(";", false),
+		})
+	}
+	writeCSV(filepath.Join(folderPath, test.testType+BlockDetailsSuffix), blockDetailsRows)
+
+	secondRows := [][]string{{"second", "sent_txs", "mined_txs", "gas_used"}}
+	for _, second := range report.Seconds {
+		secondRows = append(secondRows, []string{
+			strconv.FormatInt(second.Second, 10),
+			strconv.FormatUint(uint64(second.SentTxs), 10),
+			strconv.FormatUint(uint64(second.MinedTxs), 10),
+			strconv.FormatUint(second.GasUsed, 10),
+		})
+	}
+	writeCSV(filepath.Join(folderPath, test.testType+SecondSeriesSuffix), secondRows)
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		fmt.Printf("Could not encode report, error: %s, test: %s\n", err.Error(), test.testName)
+		return
+	}
+
+	filePath := filepath.Join(folderPath, test.testType+ReportSuffix)
+	if err = os.WriteFile(filePath, data, 0644); err != nil {
+		fmt.Printf("Could not write report file, error: %s, filepath: %s\n", err.Error(), filePath)
+	}
+}
+
+func writeCSV(filePath string, rows [][]string) {
+	file, err := os.Create(filePath)
+	if err != nil {
+		fmt.Printf("Could not create csv file, error: %s, filepath: %s\n", err.Error(), filePath)
+		return
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	if err = writer.WriteAll(rows); err != nil {
+		fmt.Printf("Could not write csv file, error: %s, filepath: %s\n", err.Error(), filePath)
+	}
+}
@@ -0,0 +1,238 @@
+package internal
+
+import (
+	"context"
+	"math/big"
+	"math/rand"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	DefaultMaxRetries       = 3
+	DefaultInitialBackoffMs = 100
+	DefaultMaxBackoffMs     = 5000
+)
+
+// RpcClient is ethclient.Client that retries requests failed with transient errors (timeouts, 429, 5xx)
+// with exponential backoff and jitter. Methods that aren't overridden are sent once.
+type RpcClient struct {
+	*ethclient.Client
+	url   string
+	retry RetryPolicy
+	// retries counts retried requests, exhausted counts requests that failed after all retries
+	retries   atomic.Int64
+	exhausted atomic.Int64
+}
+
+// RetryPolicy defines retries of transient RPC errors.
+type RetryPolicy struct {
+	maxRetries     int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+}
+
+// RetryMetrics contains retry counters of an RPC client.
+type RetryMetrics struct {
+	url       string
+	retries   int64
+	exhausted int64
+}
+
+func NewRetryPolicy(config RetryConfig) RetryPolicy {
+	policy := RetryPolicy{
+		maxRetries:     config.MaxRetries,
+		initialBackoff: time.Duration(config.InitialBackoffMs) * time.Millisecond,
+		maxBackoff:     time.Duration(config.MaxBackoffMs) * time.Millisecond,
+	}
+
+	switch {
+	case policy.maxRetries == 0:
+		policy.maxRetries = DefaultMaxRetries
+	case policy.maxRetries < 0:
+		policy.maxRetries = 0
+	}
+	if policy.initialBackoff <= 0 {
+		policy.initialBackoff = DefaultInitialBackoffMs * time.Millisecond
+	}
+	if policy.maxBackoff <= 0 {
+		policy.maxBackoff = DefaultMaxBackoffMs * time.Millisecond
+	}
+
+	return policy
+}
+
+// backoff returns delay before the retry, it doubles with every attempt up to maxBackoff,
+// half of the delay is random, so clients throttled together don't retry at the same moment.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.maxBackoff
+	if attempt < 32 && p.initialBackoff<<attempt < p.maxBackoff {
+		delay = p.initialBackoff << attempt
+	}
+
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+func NewRpcClient(client *ethclient.Client, url string, config RetryConfig) *RpcClient {
+	return &RpcClient{
+		Client: client,
+		url:    url,
+		retry:  NewRetryPolicy(config),
+	}
+}
+
+// withRetry runs request until it succeeds, fails with a non retryable error, retries are exhausted or ctx is done.
+func withRetry[T any](ctx context.Context, c *RpcClient, request func() (T, error)) (T, error) {
+	for attempt := 0; ; attempt++ {
+		result, err := request()
+		if err == nil || !isRetryableError(err) || ctx.Err() != nil {
+			return result, err
+		}
+		if attempt >= c.retry.maxRetries {
+			c.exhausted.Add(1)
+			return result, err
+		}
+
+		c.retries.Add(1)
+		timer := time.NewTimer(c.retry.backoff(attempt))
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return result, err
+		}
+	}
+}
+
+func (c *RpcClient) metrics() *RetryMetrics {
+	return &RetryMetrics{url: c.url, retries: c.retries.Load(), exhausted: c.exhausted.Load()}
+}
+
+func (c *RpcClient) BlockNumber(ctx context.Context) (uint64, error) {
+	return withRetry(ctx, c, func() (uint64, error) {
+		return c.Client.BlockNumber(ctx)
+	})
+}
+
+func (c *RpcClient) BlockByHash(ctx context.Context, hash common.Hash) (*types.Block, error) {
+	return withRetry(ctx, c, func() (*types.Block, error) {
+		return c.Client.BlockByHash(ctx, hash)
+	})
+}
+
+func (c *RpcClient) BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error) {
+	return withRetry(ctx, c, func() (*types.Block, error) {
+		return c.Client.BlockByNumber(ctx, number)
+	})
+}
+
+func (c *RpcClient) BlockReceipts(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) ([]*types.Receipt, error) {
+	return withRetry(ctx, c, func() ([]*types.Receipt, error) {
+		return c.Client.BlockReceipts(ctx, blockNrOrHash)
+	})
+}
+
+func (c *RpcClient) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return withRetry(ctx, c, func() (*types.Header, error) {
+		return c.Client.HeaderByNumber(ctx, number)
+	})
+}
+
+func (c *RpcClient) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	return withRetry(ctx, c, func() (*types.Receipt, error) {
+		return c.Client.TransactionReceipt(ctx, txHash)
+	})
+}
+
+func (c *RpcClient) NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error) {
+	return withRetry(ctx, c, func() (uint64, error) {
+		return c.Client.NonceAt(ctx, account, blockNumber)
+	})
+}
+
+func (c *RpcClient) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	return withRetry(ctx, c, func() (uint64, error) {
+		return c.Client.PendingNonceAt(ctx, account)
+	})
+}
+
+func (c *RpcClient) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return withRetry(ctx, c, func() (*big.Int, error) {
+		return c.Client.SuggestGasPrice(ctx)
+	})
+}
+
+func (c *RpcClient) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	return withRetry(ctx, c, func() (*big.Int, error) {
+		return c.Client.SuggestGasTipCap(ctx)
+	})
+}
+
+func (c *RpcClient) FeeHistory(ctx context.Context, blockCount uint64, lastBlock *big.Int, rewardPercentiles []float64) (*ethereum.FeeHistory, error) {
+	return withRetry(ctx, c, func() (*ethereum.FeeHistory, error) {
+		return c.Client.FeeHistory(ctx, blockCount, lastBlock, rewardPercentiles)
+	})
+}
+
+func (c *RpcClient) EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error) {
+	return withRetry(ctx, c, func() (uint64, error) {
+		return c.Client.EstimateGas(ctx, msg)
+	})
+}
+
+func (c *RpcClient) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	return withRetry(ctx, c, func() ([]byte, error) {
+		return c.Client.CallContract(ctx, msg, blockNumber)
+	})
+}
+
+// SendTransaction re-sends the same signed tx, a retry of the tx that reached the node is answered with "already known".
+func (c *RpcClient) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	_, err := c.sendTransactionTimed(ctx, tx)
+	return err
+}
+
+// sendTransactionTimed sends the tx like SendTransaction, returns round trip of the last attempt without retries and backoff.
+func (c *RpcClient) sendTransactionTimed(ctx context.Context, tx *types.Transaction) (time.Duration, error) {
+	var latency time.Duration
+	_, err := withRetry(ctx, c, func() (struct{}, error) {
+		start := time.Now()
+		err := c.Client.SendTransaction(ctx, tx)
+		latency = time.Since(start)
+		return struct{}{}, err
+	})
+	return latency, err
+}
+
+// CallContext sends a raw JSON-RPC request.
+func (c *RpcClient) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	_, err := withRetry(ctx, c, func() (struct{}, error) {
+		return struct{}{}, c.Client.Client().CallContext(ctx, result, method, args...)
+	})
+	return err
+}
+
+// BatchCallContext sends a JSON-RPC batch, only failure of the whole batch is retried, item errors are left in elems.
+func (c *RpcClient) BatchCallContext(ctx context.Context, elems []rpc.BatchElem) error {
+	_, err := c.batchCallTimed(ctx, elems)
+	return err
+}
+
+// batchCallTimed sends the batch like BatchCallContext, returns round trip of the last attempt without retries and backoff.
+func (c *RpcClient) batchCallTimed(ctx context.Context, elems []rpc.BatchElem) (time.Duration, error) {
+	var latency time.Duration
+	_, err := withRetry(ctx, c, func() (struct{}, error) {
+		start := time.Now()
+		err := c.Client.Client().BatchCallContext(ctx, elems)
+		latency = time.Since(start)
+		return struct{}{}, err
+	})
+	return latency, err
+}
@@ -1,16 +1,15 @@
 package internal
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/olekukonko/tablewriter"
 	"io"
-	"log"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
-	"sync/atomic"
 	"time"
 )
 
@@ -21,50 +20,82 @@ const (
 	LogsPath                     = "logs"
 	DirPerm                      = 0755
 	LogSuffix                    = "_output.log"
+	RpcLogName                   = "rpc"
+	DefaultShutdownGraceSec      = 30
 )
 
 var (
-	EmptyTests            = errors.New("no tests configured, please define at least one test")
-	NotEnoughSenders      = errors.New("insufficient senders available, check sender configuration")
-	CannotDecryptSenderPK = errors.New("failed to decrypt sender's private key, verify the provided key")
+	EmptyTests                = errors.New("no tests configured, please define at least one test")
+	NotEnoughSenders          = errors.New("insufficient senders available, check sender configuration")
+	CannotDecryptSenderPK     = errors.New("failed to decrypt sender's private key, verify the provided key")
+	RemoteSignerNotConfigured = errors.New("sender addresses require remote signer, set senders.signer.url")
+	Interrupted               = errors.New("interrupted before sending transactions")
 )
 
 type Runner struct {
 	config        Config
-	client        *ethclient.Client
+	client        *RpcClient
+	endpoints     *EndpointPool
+	heads         *HeadTracker
 	tests         []Test
 	senders       []*Sender
 	metrics       []*Metrics
 	totalTxsCount int
+	retryMetrics  []*RetryMetrics
 	errors        []error
 }
 
-func NewRunner(config Config, client *ethclient.Client) *Runner {
+func NewRunner(config Config, client *RpcClient, endpoints *EndpointPool) *Runner {
 	return &Runner{
-		config: config,
-		client: client,
+		config:    config,
+		client:    client,
+		endpoints: endpoints,
+		heads:     NewHeadTracker(client, config.App.Node.HeadPollIntervalMs),
 	}
 }
 
-func (r *Runner) Start() error {
+// Start prepares and runs tests and reports results. When ctx is canceled (SIGINT/SIGTERM), sending stops,
+// receipts of sent txs are collected within the shutdown grace period and the partial report is written.
+func (r *Runner) Start(ctx context.Context) error {
 	if len(r.config.Tests) == 0 {
 		return EmptyTests
 	}
 
+	if err := r.heads.Start(); err != nil {
+		return err
+	}
+	defer r.heads.Stop()
+
 	fmt.Println("Start Preparing data")
 	handleErrors(&r.errors, r.PrepareSenders())
 	handleErrors(&r.errors, r.PrepareTests())
-	handleErrors(&r.errors, r.PrepareTransactions())
+	handleErrors(&r.errors, r.PrepareTransactions(ctx))
+	if ctx.Err() != nil {
+		return Interrupted
+	}
+	// nothing is sent with invalid config
+	if len(r.errors) > 0 {
+		return fmt.Errorf("failed to prepare tests: %w", errors.Join(r.errors...))
+	}
 
 	fmt.Println("Tests Are Prepared")
-	handleErrors(&r.errors, r.Run())
+	r.endpoints.StartHealthChecks()
+	handleErrors(&r.errors, r.Run(ctx))
+	r.endpoints.StopHealthChecks()
 	fmt.Println("Txs Were Sent")
 
+	collectCtx, cancel := r.collectContext(ctx)
+	defer cancel()
+
 	fmt.Println("Begin Collect Metrics.")
-	handleErrors(&r.errors, r.CollectData())
+	handleErrors(&r.errors, r.CollectData(collectCtx))
 	handleErrors(&r.errors, r.CollectMetrics())
 	r.Output()
 
+	if ctx.Err() != nil {
+		fmt.Println("Run was interrupted, the report is partial")
+	}
+
 	if len(r.errors) > 0 {
 		fmt.Println("Errors occurred:")
 		for _, err := range r.errors {
@@ -75,16 +106,15 @@ func (r *Runner) Start() error {
 	return nil
 }
 
-// PrepareSenders initializes sender entities from the provided private keys in the configuration.
+// PrepareSenders initializes sender entities from the provided private keys and remote signer addresses in the configuration.
 func (r *Runner) PrepareSenders() error {
-	// Iterate over private keys to initialize sender entities
 	fmt.Println("Preparing Senders")
-	for _, senderPK := range r.config.Senders.PrivateKeys {
-		sender, err := NewSender(r.client, senderPK)
-		if err != nil {
-			return CannotDecryptSenderPK
-		}
+	senders, err := NewSenders(r.client, r.config.Senders)
+	if err != nil {
+		return err
+	}
 
+	for _, sender := range senders {
 		err = sender.defineCurrentSenderNonce(sender)
 		if err != nil {
 			return fmt.Errorf("failed to set sender nonce: %w", err)
@@ -95,19 +125,75 @@ func (r *Runner) PrepareSenders() error {
 	return nil
 }
 
-// PrepareTests initializes test cases from the configuration and validates sender availability.
+// PrepareTests initializes test cases from the configuration and allocates sender pools.
+// With disjoint pools, tests never share senders, so failures of one test can't break nonce sequence of another.
 func (r *Runner) PrepareTests() error {
 	fmt.Println("Preparing Tests")
-	r.totalTxsCount = 0
-	for configName, configTest := range r.config.Tests {
-		test := NewTest(r.client, r.config.App.Node.ChainID, configName, configTest)
-		r.totalTxsCount += test.txsCount
+	testNames := make([]string, 0, len(r.config.Tests))
+	for configName := range r.config.Tests {
+		testNames = append(testNames, configName)
+	}
+	sort.Strings(testNames)
+
+	usedSenders := make(map[int]string)
+	testSenders := make(map[string][]int)
+
+	// explicitly selected senders first, so count based tests get the rest
+	for _, configName := range testNames {
+		configTest := r.config.Tests[configName]
+		indices, err := resolveSenderIndices(configTest.Config, r.config.Senders.Groups, len(r.senders))
+		if err != nil {
+			return fmt.Errorf("test '%s': %w", configName, err)
+		}
+		if len(indices) == 0 {
+			continue
+		}
+
+		for _, index := range indices {
+			if usedBy, used := usedSenders[index]; used && r.config.Senders.DisjointPools {
+				return fmt.Errorf("test '%s': sender %d is already used by test '%s' (disjoint_pools is enabled)", configName, index, usedBy)
+			}
+			usedSenders[index] = configName
+		}
+		testSenders[configName] = indices
+	}
+
+	for _, configName := range testNames {
+		configTest := r.config.Tests[configName]
+		if _, exists := testSenders[configName]; exists {
+			continue
+		}
 
-		if configTest.Config.Senders > len(r.config.Senders.PrivateKeys) {
+		if configTest.Config.Senders > len(r.senders) {
 			return NotEnoughSenders
 		}
 
-		test.senders = r.senders[0:configTest.Config.Senders]
+		var indices []int
+		for index := 0; index < len(r.senders) && len(indices) < configTest.Config.Senders; index++ {
+			if _, used := usedSenders[index]; used && r.config.Senders.DisjointPools {
+				continue
+			}
+			indices = append(indices, index)
+		}
+		if len(indices) < configTest.Config.Senders {
+			return fmt.Errorf("test '%s': %w (%d unused senders left for disjoint pools)", configName, NotEnoughSenders, len(indices))
+		}
+
+		for _, index := range indices {
+			usedSenders[index] = configName
+		}
+		testSenders[configName] = indices
+	}
+
+	for _, configName := range testNames {
+		test, err := NewTest(r.client, r.endpoints, r.heads, r.config.App.Node.ChainID, configName, r.config.Tests[configName])
+		if err != nil {
+			return fmt.Errorf("test '%s': %w", configName, err)
+		}
+
+		for _, index := range testSenders[configName] {
+			test.senders = append(test.senders, r.senders[index])
+		}
 
 		r.tests = append(r.tests, *test)
 	}
@@ -116,7 +202,7 @@ func (r *Runner) PrepareTests() error {
 }
 
 // PrepareTransactions signs transactions for each test case, ensuring they are ready to be sent.
-func (r *Runner) PrepareTransactions() error {
+func (r *Runner) PrepareTransactions(ctx context.Context) error {
 	fmt.Println("Prepare And Signing Transactions")
 	for i := range r.tests {
 		test := &r.tests[i]
@@ -126,17 +212,23 @@ func (r *Runner) PrepareTransactions() error {
 
 		var err error
 		if test.testType == SEND {
-			err = test.SignTransactions()
+			err = test.SignTransactions(ctx)
 			if err != nil {
 				return err
 			}
 		}
+		// call data is packed before sending, so bad params fail before any test runs
+		if test.testType == CALL {
+			if err = test.contract.prepare(); err != nil {
+				return fmt.Errorf("test '%s': contract call message generation error: %w", test.testName, err)
+			}
+		}
 	}
 
 	return nil
 }
 
-func (r *Runner) Run() error {
+func (r *Runner) Run(ctx context.Context) error {
 	if len(r.tests) == 0 {
 		return EmptyTests
 	}
@@ -144,13 +236,34 @@ func (r *Runner) Run() error {
 	fmt.Println("Begin Sending Transactions")
 
 	for testIdx := range r.tests {
+		if ctx.Err() != nil {
+			// tests that didn't start are left out of the report
+			for _, test := range r.tests[testIdx:] {
+				fmt.Printf("Test %s skipped, run was interrupted \n", test.testName)
+			}
+			r.tests = r.tests[:testIdx]
+			break
+		}
+
 		test := &r.tests[testIdx]
-		err := test.Run()
+		err := test.Run(ctx)
 		if err != nil {
-			return err
+			// the failed test and tests after it are left out of the report
+			for _, skipped := range r.tests[testIdx+1:] {
+				fmt.Printf("Test %s skipped, test %s failed \n", skipped.testName, test.testName)
+			}
+			r.tests = r.tests[:testIdx]
+			return fmt.Errorf("test '%s': %w", test.testName, err)
 		}
 	}
 
+	if ctx.Err() != nil {
+		fmt.Println("Sending Was Interrupted")
+	}
+	if len(r.tests) == 0 {
+		return nil
+	}
+
 	fmt.Println("Finish Sending Transactions")
 	fmt.Println("Start Block: ", r.tests[0].startBlock)
 	fmt.Println("End Block: ", r.tests[0].endBlock)
@@ -159,11 +272,39 @@ func (r *Runner) Run() error {
 }
 
 // CollectData retrieves transaction data from the blockchain, tracking the status of sent transactions.
-func (r *Runner) CollectData() error {
-	time.Sleep(time.Duration(AttemptsToCollectIntervalSec) * time.Second)
-
+// Receipts of most txs are already collected during sending, only txs in flight are waited for.
+func (r *Runner) CollectData(ctx context.Context) error {
 	fmt.Println("Begin Collect Data")
-	var totalCollectedTxCount int32
+
+	// only txs of tests that ran are expected, tests skipped after interruption or failure are left out
+	r.totalTxsCount = 0
+	for _, test := range r.tests {
+		if test.testType == SEND {
+			r.totalTxsCount += test.txsCount
+		}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+
+			var collected, tracked int32
+			for i := range r.tests {
+				if collector := r.tests[i].collector; collector != nil {
+					collected += collector.collected.Load()
+					tracked += collector.tracked.Load()
+				}
+			}
+			fmt.Printf("Collected transactions: %d/%d (sent %d)\n", collected, r.totalTxsCount, tracked)
+		}
+	}()
 
 	for i := range r.tests {
 		test := &r.tests[i]
@@ -172,21 +313,44 @@ func (r *Runner) CollectData() error {
 			continue
 		}
 
-		go func() {
-			for {
-				fmt.Printf("Collected transactions: %d/%d\n", atomic.LoadInt32(&totalCollectedTxCount), r.totalTxsCount)
-				time.Sleep(time.Second)
-			}
-		}()
-
-		handleErrors(&r.errors, test.CollectData(&totalCollectedTxCount))
+		handleErrors(&r.errors, test.CollectData(ctx))
 	}
+	close(done)
 
 	fmt.Println("End Collect Data")
 
 	return nil
 }
 
+// collectContext returns context of data collection, it isn't canceled with ctx immediately,
+// but after the shutdown grace period, so receipts of already sent txs can still be collected.
+func (r *Runner) collectContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	grace := time.Duration(r.config.App.ShutdownGraceSec) * time.Second
+	if grace <= 0 {
+		grace = DefaultShutdownGraceSec * time.Second
+	}
+
+	collectCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	go func() {
+		select {
+		case <-ctx.Done():
+			fmt.Printf("Collecting receipts of sent txs for up to %s, interrupt again to exit immediately \n", grace)
+		case <-collectCtx.Done():
+			return
+		}
+
+		timer := time.NewTimer(grace)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+			cancel()
+		case <-collectCtx.Done():
+		}
+	}()
+
+	return collectCtx, cancel
+}
+
 // CollectMetrics computes performance metrics such as TPS, gas usage, and transaction success rates.
 func (r *Runner) CollectMetrics() error {
 	for i := range r.tests {
@@ -200,6 +364,17 @@ func (r *Runner) CollectMetrics() error {
 
 	}
 
+	// retries are counted per client for the whole run, endpoints may share the primary client
+	clients := []*RpcClient{r.client}
+	for _, endpoint := range r.endpoints.endpoints {
+		if endpoint.client != r.client {
+			clients = append(clients, endpoint.client)
+		}
+	}
+	for _, client := range clients {
+		r.retryMetrics = append(r.retryMetrics, client.metrics())
+	}
+
 	return nil
 }
 
@@ -217,7 +392,7 @@ func (r *Runner) Output() {
 			filePath := filepath.Join(folderPath, fileName)
 			file, err = os.Create(filePath)
 			if err != nil {
-				log.Fatal(err)
+				fmt.Printf("Could not create log file, error: %s, filepath: %s\n", err.Error(), filePath)
 			}
 		}
 
@@ -241,9 +416,37 @@ func (r *Runner) Output() {
 		if file != nil {
 			handleErrors(&r.errors, file.Close())
 		}
+
+		if test.testType == SEND {
+			r.writeReport(folderPath, test)
+		}
+	}
+
+	r.outputRetries(os.Stdout)
+	if err := os.MkdirAll(LogsPath, DirPerm); err == nil {
+		filePath := filepath.Join(LogsPath, RpcLogName+LogSuffix)
+		file, err := os.Create(filePath)
+		if err != nil {
+			fmt.Printf("Could not create log file, error: %s, filepath: %s\n", err.Error(), filePath)
+			return
+		}
+		r.outputRetries(file)
+		handleErrors(&r.errors, file.Close())
 	}
 }
 
+// outputRetries renders retries of transient RPC errors of the whole run.
+func (r *Runner) outputRetries(writer io.Writer) {
+	tableRetries := tablewriter.NewWriter(writer)
+	tableRetries.SetHeader([]string{"RPC Client", "Retries", "Failed After Retries"})
+	tableRetries.AppendBulk(r.getRetriesOutputData())
+
+	fmt.Fprint(writer, "\n\n============================================\n")
+	fmt.Fprint(writer, "RPC retries (timeouts, 429, 5xx) \n")
+	fmt.Fprint(writer, "============================================\n\n")
+	tableRetries.Render()
+}
+
 func (r *Runner) outputSend(writer io.Writer, test Test) {
 	data, dataBlocks := r.getSendOutputData(test)
 
@@ -261,6 +464,79 @@ func (r *Runner) outputSend(writer io.Writer, test Test) {
 	tableSummary.Render()
 	fmt.Fprintln(writer, "Block distance (average, distance between block when tx wax sent and block when tx was mined): ")
 	tableBlocks.Render()
+
+	if test.metrics.timeToInclude.total != 0 {
+		tablePercentiles := tablewriter.NewWriter(writer)
+		tablePercentiles.SetHeader([]string{"Percentile", "Mine Time (s)", "Block Distance"})
+		tablePercentiles.AppendBulk(r.getPercentilesOutputData(test))
+		fmt.Fprintln(writer, "Inclusion percentiles (mined txs): ")
+		tablePercentiles.Render()
+
+		tableHistogram := tablewriter.NewWriter(writer)
+		tableHistogram.SetHeader([]string{"Mine Time (ms)", "Txs Count", "Cumulative (%)"})
+		tableHistogram.AppendBulk(r.getHistogramOutputData(test.metrics.timeToInclude))
+		fmt.Fprintln(writer, "Mine time histogram: ")
+		tableHistogram.Render()
+	}
+
+	if test.metrics.sendLatency.total != 0 {
+		tableSendLatency := tablewriter.NewWriter(writer)
+		tableSendLatency.SetHeader([]string{"Percentile", "Send Latency (ms)"})
+		tableSendLatency.AppendBulk(r.getLatencyPercentilesOutputData(test.metrics.sendLatency))
+		fmt.Fprintln(writer, "Send RPC latency percentiles (eth_sendRawTransaction round trip of the last attempt, batch round trip when batched): ")
+		tableSendLatency.Render()
+	}
+
+	if len(test.metrics.sendErrors) != 0 {
+		tableSendErrors := tablewriter.NewWriter(writer)
+		tableSendErrors.SetHeader([]string{"Send Error", "Count", "First Seen"})
+		tableSendErrors.AppendBulk(r.getSendErrorsOutputData(test))
+		fmt.Fprintln(writer, "Send errors: ")
+		tableSendErrors.Render()
+	}
+
+	if len(test.metrics.blocks) != 0 {
+		tableBlockDetails := tablewriter.NewWriter(writer)
+		tableBlockDetails.SetHeader([]string{"Block", "Timestamp", "Block Time (s)", "Txs", "Test Txs", "Gas Used", "Gas Limit", "Utilization (%)", "Base Fee (gwei)", "Test Nonces"})
+		tableBlockDetails.AppendBulk(r.getBlockDetailsOutputData(test))
+		fmt.Fprintln(writer, "Blocks of the test window: ")
+		tableBlockDetails.Render()
+		if len(test.metrics.blocks) > MaxBlockDetailsRows {
+			fmt.Fprintf(writer, "First %d of %d blocks are shown, all blocks are in %s%s \n", MaxBlockDetailsRows, len(test.metrics.blocks), test.testType, BlockDetailsSuffix)
+		}
+	}
+
+	if len(test.metrics.senders) != 0 {
+		tableSenders := tablewriter.NewWriter(writer)
+		tableSenders.SetHeader([]string{"Sender", "Sent Txs", "Mined Txs", "Failed Txs", "Send Failed Txs", "Dropped Txs", "Mine Time (avg, s)", "Mine Time (p99, s)", "Nonces"})
+		tableSenders.AppendBulk(r.getSendersOutputData(test))
+		fmt.Fprintln(writer, "Senders: ")
+		tableSenders.Render()
+	}
+
+	if len(test.metrics.functions) != 0 {
+		tableFunctions := tablewriter.NewWriter(writer)
+		tableFunctions.SetHeader([]string{"Function", "Sent Txs", "Success Rate (%)", "Gas Used (avg)", "Mine Time (avg, s)"})
+		tableFunctions.AppendBulk(r.getSendFunctionsOutputData(test))
+		fmt.Fprintln(writer, "Functions: ")
+		tableFunctions.Render()
+	}
+
+	if len(test.metrics.endpoints) != 0 {
+		tableEndpoints := tablewriter.NewWriter(writer)
+		tableEndpoints.SetHeader([]string{"Endpoint", "Sent Txs", "Send Errors", "RPC Latency (avg, ms)", "Mined Txs", "Mine Time (avg, s)", "Status"})
+		tableEndpoints.AppendBulk(r.getEndpointsOutputData(test))
+		fmt.Fprintln(writer, "Endpoints: ")
+		tableEndpoints.Render()
+	}
+
+	if test.metrics.replacements != nil {
+		tableReplacements := tablewriter.NewWriter(writer)
+		tableReplacements.SetHeader([]string{"Replacement Metric", "Result"})
+		tableReplacements.AppendBulk(r.getReplacementsOutputData(test))
+		fmt.Fprintln(writer, "Replacements (same nonce, bumped fee): ")
+		tableReplacements.Render()
+	}
 }
 
 func (r *Runner) outputCall(writer io.Writer, test Test) {
@@ -278,6 +554,30 @@ func (r *Runner) outputCall(writer io.Writer, test Test) {
 	fmt.Fprintln(writer, "============================================")
 
 	tableSummary.Render()
+
+	if test.callMetrics.latency.total != 0 {
+		tablePercentiles := tablewriter.NewWriter(writer)
+		tablePercentiles.SetHeader([]string{"Percentile", "Latency (ms)"})
+		tablePercentiles.AppendBulk(r.getLatencyPercentilesOutputData(test.callMetrics.latency))
+		fmt.Fprintln(writer, "Call latency percentiles (received results): ")
+		tablePercentiles.Render()
+	}
+
+	if len(test.callMetrics.errors) != 0 {
+		tableErrors := tablewriter.NewWriter(writer)
+		tableErrors.SetHeader([]string{"Error", "Calls", "First Seen"})
+		tableErrors.AppendBulk(r.getCallErrorsOutputData(test))
+		fmt.Fprintln(writer, "Call errors: ")
+		tableErrors.Render()
+	}
+
+	if len(test.callMetrics.functions) != 0 {
+		tableFunctions := tablewriter.NewWriter(writer)
+		tableFunctions.SetHeader([]string{"Function", "Calls", "Success Rate (%)", "Invalid Results", "Latency (avg, ms)", "Latency (p99, ms)"})
+		tableFunctions.AppendBulk(r.getCallFunctionsOutputData(test))
+		fmt.Fprintln(writer, "Functions: ")
+		tableFunctions.Render()
+	}
 }
 
 func (r *Runner) getSendOutputData(test Test) ([][]string, [][]string) {
@@ -287,12 +587,26 @@ func (r *Runner) getSendOutputData(test Test) ([][]string, [][]string) {
 	data = append(data, []string{"Start Block", strconv.Itoa(int(test.startBlock))})
 	data = append(data, []string{"End Block", strconv.Itoa(int(test.endBlock))})
 	data = append(data, []string{"TPS (in config)", strconv.Itoa(int(test.metrics.configTps))})
+	data = append(data, []string{"TPS (sent)", strconv.FormatFloat(test.metrics.sentTps, 'f', 2, 64)})
+	data = append(data, []string{"TPS (mined)", strconv.FormatFloat(test.metrics.realTps, 'f', 2, 64)})
+	data = append(data, []string{"Mgas/s (mined txs)", strconv.FormatFloat(test.metrics.mgasPerSec, 'f', 3, 64)})
 	data = append(data, []string{"TXs In Block (avg)", strconv.Itoa(int(test.metrics.avgTxsPerBlock))})
 	data = append(data, []string{"TXs Mine Time (avg, s)", strconv.FormatFloat(float64(test.metrics.avgTimeToInclude)/1000.0, 'f', 3, 64)})
 	data = append(data, []string{"Gas Price per Tx (avg)", strconv.Itoa(int(test.metrics.avgGasPricePerTx))})
 	data = append(data, []string{"Gas Usage per Block (avg)", strconv.Itoa(int(test.metrics.avgGasUsedPerBlock))})
 	data = append(data, []string{"Success Txs", strconv.Itoa(int(test.metrics.succeedTxs))})
 	data = append(data, []string{"Failed Txs", strconv.Itoa(int(test.metrics.failedTxs))})
+	data = append(data, []string{"Send Failed Txs", strconv.Itoa(int(test.metrics.sendFailedTxs))})
+	data = append(data, []string{"Lost To Nonce Gap Txs", strconv.Itoa(int(test.metrics.gapLostTxs))})
+	data = append(data, []string{"Not Mined Txs", strconv.Itoa(int(test.metrics.notMinedTxs))})
+	data = append(data, []string{"Nonce Re-syncs", strconv.Itoa(int(test.metrics.nonceResyncs))})
+	if test.metrics.notSentTxs != 0 {
+		data = append(data, []string{"Not Sent Txs (interrupted)", strconv.Itoa(int(test.metrics.notSentTxs))})
+	}
+	data = append(data, []string{"Sender Fairness (mined share)", strconv.FormatFloat(test.metrics.minedShareFairness, 'f', 3, 64)})
+	data = append(data, []string{"Sender Fairness (mine time)", strconv.FormatFloat(test.metrics.mineTimeFairness, 'f', 3, 64)})
+	data = append(data, []string{"Sender Mined Share (min/max)", strconv.FormatFloat(test.metrics.minedShareSpread, 'f', 3, 64)})
+	data = append(data, []string{"Sender Mine Time (min/max)", strconv.FormatFloat(test.metrics.mineTimeSpread, 'f', 3, 64)})
 
 	var i uint64 = 0
 	processedBlocks := make(map[uint64]bool)
@@ -311,6 +625,45 @@ func (r *Runner) getSendOutputData(test Test) ([][]string, [][]string) {
 	return data, dataBlocks
 }
 
+func (r *Runner) getPercentilesOutputData(test Test) [][]string {
+	var data [][]string
+
+	for _, percentile := range ReportPercentiles {
+		data = append(data, []string{
+			fmt.Sprintf("p%g", percentile),
+			strconv.FormatFloat(float64(test.metrics.timeToInclude.percentile(percentile))/1000.0, 'f', 3, 64),
+			strconv.FormatInt(test.metrics.blockDistance.percentile(percentile), 10),
+		})
+	}
+	data = append(data, []string{
+		"max",
+		strconv.FormatFloat(float64(test.metrics.timeToInclude.max)/1000.0, 'f', 3, 64),
+		strconv.FormatInt(test.metrics.blockDistance.max, 10),
+	})
+
+	return data
+}
+
+func (r *Runner) getHistogramOutputData(histogram *Histogram) [][]string {
+	var data [][]string
+
+	var cumulative uint64
+	for _, bucket := range histogram.powerOfTwoBuckets() {
+		cumulative += bucket.count
+		bucketRange := strconv.FormatInt(bucket.low, 10)
+		if bucket.high != bucket.low {
+			bucketRange = fmt.Sprintf("%d - %d", bucket.low, bucket.high)
+		}
+		data = append(data, []string{
+			bucketRange,
+			strconv.FormatUint(bucket.count, 10),
+			strconv.FormatFloat(float64(cumulative)*100/float64(histogram.total), 'f', 2, 64),
+		})
+	}
+
+	return data
+}
+
 func (r *Runner) getCallOutputData(test Test) [][]string {
 	var data [][]string
 
@@ -321,6 +674,203 @@ func (r *Runner) getCallOutputData(test Test) [][]string {
 	data = append(data, []string{"Total Sent Calls", strconv.Itoa(test.callMetrics.callSentCount)})
 	data = append(data, []string{"Total Received Results", strconv.Itoa(test.callMetrics.callReceiveCount)})
 	data = append(data, []string{"Total Error Calls", strconv.Itoa(test.callMetrics.callErrorsCount)})
+	data = append(data, []string{"Invalid Results", strconv.Itoa(test.callMetrics.invalidResults)})
+	data = append(data, []string{"Calls/s (achieved)", strconv.FormatFloat(test.callMetrics.callsPerSecond(), 'f', 2, 64)})
+	data = append(data, []string{"Latency (avg, ms)", strconv.FormatFloat(test.callMetrics.latency.mean()/1000.0, 'f', 3, 64)})
+
+	return data
+}
+
+func (r *Runner) getSendFunctionsOutputData(test Test) [][]string {
+	var data [][]string
+
+	for _, function := range test.metrics.functions {
+		successRate := 0.0
+		if function.sentTxs != 0 {
+			successRate = float64(function.succeedTxs) / float64(function.sentTxs) * 100
+		}
+
+		data = append(data, []string{
+			function.label,
+			strconv.Itoa(int(function.sentTxs)),
+			strconv.FormatFloat(successRate, 'f', 2, 64),
+			strconv.Itoa(int(function.avgGasUsed)),
+			strconv.FormatFloat(float64(function.avgTimeToInclude)/1000.0, 'f', 3, 64),
+		})
+	}
+
+	return data
+}
+
+// getLatencyPercentilesOutputData renders percentiles of latency recorded in µs as ms.
+func (r *Runner) getLatencyPercentilesOutputData(latency *Histogram) [][]string {
+	var data [][]string
+
+	for _, percentile := range ReportPercentiles {
+		data = append(data, []string{
+			fmt.Sprintf("p%g", percentile),
+			strconv.FormatFloat(float64(latency.percentile(percentile))/1000.0, 'f', 3, 64),
+		})
+	}
+	data = append(data, []string{"max", strconv.FormatFloat(float64(latency.max)/1000.0, 'f', 3, 64)})
+
+	return data
+}
+
+func (r *Runner) getSendErrorsOutputData(test Test) [][]string {
+	var data [][]string
+
+	for _, sendError := range test.metrics.sendErrors {
+		data = append(data, []string{sendError.category, strconv.Itoa(int(sendError.count)), sendError.firstSeen})
+	}
+
+	return data
+}
+
+// getCallErrorsOutputData returns call error groups with the first seen message, most frequent first.
+func (r *Runner) getCallErrorsOutputData(test Test) [][]string {
+	messages := make([]string, 0, len(test.callMetrics.errors))
+	for message := range test.callMetrics.errors {
+		messages = append(messages, message)
+	}
+	sort.Slice(messages, func(i, j int) bool {
+		if test.callMetrics.errors[messages[i]] != test.callMetrics.errors[messages[j]] {
+			return test.callMetrics.errors[messages[i]] > test.callMetrics.errors[messages[j]]
+		}
+		return messages[i] < messages[j]
+	})
+
+	var data [][]string
+	for _, message := range messages {
+		data = append(data, []string{message, strconv.Itoa(test.callMetrics.errors[message]), test.callMetrics.errorsFirstSeen[message]})
+	}
+
+	return data
+}
+
+func (r *Runner) getCallFunctionsOutputData(test Test) [][]string {
+	var data [][]string
+
+	for _, function := range test.contract.functions {
+		functionMetrics, exists := test.callMetrics.functions[function.label]
+		if !exists {
+			continue
+		}
+
+		successRate := 0.0
+		if functionMetrics.calls != 0 {
+			successRate = float64(functionMetrics.calls-functionMetrics.errors-functionMetrics.invalidResults) / float64(functionMetrics.calls) * 100
+		}
+
+		data = append(data, []string{
+			function.label,
+			strconv.Itoa(functionMetrics.calls),
+			strconv.FormatFloat(successRate, 'f', 2, 64),
+			strconv.Itoa(functionMetrics.invalidResults),
+			strconv.FormatFloat(functionMetrics.latency.mean()/1000.0, 'f', 3, 64),
+			strconv.FormatFloat(float64(functionMetrics.latency.percentile(99))/1000.0, 'f', 3, 64),
+		})
+	}
+
+	return data
+}
+
+func (r *Runner) getReplacementsOutputData(test Test) [][]string {
+	var data [][]string
+	replacements := test.metrics.replacements
+
+	data = append(data, []string{"Sent Replacements", strconv.Itoa(int(replacements.sentReplacements))})
+	data = append(data, []string{"Accepted By Node", strconv.Itoa(int(replacements.acceptedReplacements))})
+	data = append(data, []string{"Rejected By Node", strconv.Itoa(int(replacements.rejectedReplacements))})
+	data = append(data, []string{"Mined Original", strconv.Itoa(int(replacements.minedOriginal))})
+	data = append(data, []string{"Mined Replacement", strconv.Itoa(int(replacements.minedReplacement))})
+	data = append(data, []string{"Not Mined", strconv.Itoa(int(replacements.notMined))})
+	data = append(data, []string{"Replaced TXs Mine Time (avg, s)", strconv.FormatFloat(float64(replacements.avgTimeToInclude)/1000.0, 'f', 3, 64)})
+	data = append(data, []string{"Latency Penalty (avg, s)", strconv.FormatFloat(float64(replacements.latencyPenalty)/1000.0, 'f', 3, 64)})
+
+	return data
+}
+
+// getBlockDetailsOutputData returns first MaxBlockDetailsRows blocks of the test window.
+func (r *Runner) getBlockDetailsOutputData(test Test) [][]string {
+	var data [][]string
+
+	for _, block := range test.metrics.blocks[:min(len(test.metrics.blocks), MaxBlockDetailsRows)] {
+		data = append(data, []string{
+			strconv.FormatUint(block.number, 10),
+			strconv.FormatUint(block.timestamp, 10),
+			block.timeDeltaString(),
+			strconv.Itoa(block.txs),
+			strconv.Itoa(block.testTxs),
+			strconv.FormatUint(block.gasUsed, 10),
+			strconv.FormatUint(block.gasLimit, 10),
+			strconv.FormatFloat(block.utilization(), 'f', 2, 64),
+			block.baseFeeGwei(),
+			block.senderNoncesString("\n", true),
+		})
+	}
+
+	return data
+}
+
+func (r *Runner) getSendersOutputData(test Test) [][]string {
+	var data [][]string
+
+	for _, sender := range test.metrics.senders {
+		nonces := "-"
+		if sender.sentTxs != 0 {
+			nonces = fmt.Sprintf("%d - %d", sender.firstNonce, sender.lastNonce)
+		}
+
+		data = append(data, []string{
+			sender.address,
+			strconv.Itoa(int(sender.sentTxs)),
+			strconv.Itoa(int(sender.minedTxs)),
+			strconv.Itoa(int(sender.failedTxs)),
+			strconv.Itoa(int(sender.sendFailedTxs)),
+			strconv.Itoa(int(sender.droppedTxs)),
+			strconv.FormatFloat(sender.timeToInclude.mean()/1000.0, 'f', 3, 64),
+			strconv.FormatFloat(float64(sender.timeToInclude.percentile(99))/1000.0, 'f', 3, 64),
+			nonces,
+		})
+	}
+
+	return data
+}
+
+func (r *Runner) getEndpointsOutputData(test Test) [][]string {
+	var data [][]string
+
+	for _, endpoint := range test.metrics.endpoints {
+		status := "healthy"
+		if !endpoint.healthy {
+			status = "ejected"
+		}
+
+		data = append(data, []string{
+			endpoint.url,
+			strconv.Itoa(int(endpoint.sentTxs)),
+			strconv.Itoa(int(endpoint.sendErrors)),
+			strconv.FormatFloat(endpoint.avgRpcLatency, 'f', 3, 64),
+			strconv.Itoa(int(endpoint.minedTxs)),
+			strconv.FormatFloat(float64(endpoint.avgTimeToInclude)/1000.0, 'f', 3, 64),
+			status,
+		})
+	}
+
+	return data
+}
+
+func (r *Runner) getRetriesOutputData() [][]string {
+	var data [][]string
+
+	for _, retries := range r.retryMetrics {
+		data = append(data, []string{
+			retries.url,
+			strconv.FormatInt(retries.retries, 10),
+			strconv.FormatInt(retries.exhausted, 10),
+		})
+	}
 
 	return data
 }
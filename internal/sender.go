@@ -6,18 +6,36 @@ import (
 	"fmt"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/ethereum/go-ethereum/ethclient"
+	"sync/atomic"
 )
 
 type Sender struct {
-	client          *ethclient.Client
+	client          *RpcClient
 	Address         *common.Address
 	PrivateKey      string
-	PrivateKeyEcdsa *ecdsa.PrivateKey
+	PrivateKeyEcdsa *ecdsa.PrivateKey // nil for senders signed by remote signer
+	Signer          TxSigner
 	Nonce           uint64
+	// needsResync is set after send failure, nonce is re-synced with the node before the next send
+	needsResync atomic.Bool
+	// nonceChanged is set when queued txs were re-signed with other nonces
+	nonceChanged bool
 }
 
-func NewSender(client *ethclient.Client, senderPk string) (*Sender, error) {
+// SenderMetrics contains send and inclusion results of txs of a single sender.
+type SenderMetrics struct {
+	address       string
+	sentTxs       uint
+	minedTxs      uint
+	failedTxs     uint       // mined, but reverted
+	sendFailedTxs uint       // rejected by the node on send
+	droppedTxs    uint       // accepted by the node, but not mined (nonce gap, dropped or still pending)
+	timeToInclude *Histogram // in ms
+	firstNonce    uint64
+	lastNonce     uint64
+}
+
+func NewSender(client *RpcClient, senderPk string) (*Sender, error) {
 	privateKey, err := crypto.HexToECDSA(senderPk)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load private key: %w", err)
@@ -31,9 +49,60 @@ func NewSender(client *ethclient.Client, senderPk string) (*Sender, error) {
 		Address:         &fromAddress,
 		PrivateKey:      senderPk,
 		PrivateKeyEcdsa: privateKey,
+		Signer:          NewLocalSigner(privateKey),
 	}, nil
 }
 
+// NewRemoteSender creates sender which keys are kept by the remote signer.
+func NewRemoteSender(client *RpcClient, address string, signer TxSigner) (*Sender, error) {
+	if !common.IsHexAddress(address) {
+		return nil, fmt.Errorf("invalid sender address: %s", address)
+	}
+
+	fromAddress := common.HexToAddress(address)
+
+	return &Sender{
+		client:  client,
+		Address: &fromAddress,
+		Signer:  signer,
+	}, nil
+}
+
+// NewSenders creates senders from private keys first, then senders of the remote signer from addresses.
+func NewSenders(client *RpcClient, config SendersConfig) ([]*Sender, error) {
+	var senders []*Sender
+	for _, senderPK := range config.PrivateKeys {
+		sender, err := NewSender(client, senderPK)
+		if err != nil {
+			return nil, CannotDecryptSenderPK
+		}
+		senders = append(senders, sender)
+	}
+
+	if len(config.Addresses) == 0 {
+		return senders, nil
+	}
+
+	if config.Signer.URL == "" {
+		return nil, RemoteSignerNotConfigured
+	}
+
+	signer, err := NewRemoteSigner(config.Signer.URL, config.Signer.Method)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, address := range config.Addresses {
+		sender, err := NewRemoteSender(client, address, signer)
+		if err != nil {
+			return nil, err
+		}
+		senders = append(senders, sender)
+	}
+
+	return senders, nil
+}
+
 func (s *Sender) defineCurrentSenderNonce(sender *Sender) error {
 	nonce, err := s.client.PendingNonceAt(context.Background(), *sender.Address)
 	if err != nil {
@@ -43,3 +112,106 @@ func (s *Sender) defineCurrentSenderNonce(sender *Sender) error {
 	sender.Nonce = nonce
 	return nil
 }
+
+// collectSenderMetrics breaks down send and inclusion results by sender, in the order of test senders.
+func (t *Test) collectSenderMetrics(blockTimes map[uint64]uint64) []*SenderMetrics {
+	sendersMetrics := make([]*SenderMetrics, 0, len(t.senders))
+	for _, sender := range t.senders {
+		metrics := &SenderMetrics{address: sender.Address.Hex(), timeToInclude: NewHistogram()}
+
+		for _, tx := range t.senderTransactions[sender.Address.String()] {
+			if tx.sentTimestamp == 0 {
+				continue
+			}
+
+			nonce := tx.clientTransaction.Nonce()
+			if metrics.sentTxs == 0 || nonce < metrics.firstNonce {
+				metrics.firstNonce = nonce
+			}
+			metrics.lastNonce = max(metrics.lastNonce, nonce)
+			metrics.sentTxs++
+
+			switch {
+			case tx.receipt != nil:
+				metrics.minedTxs++
+				if tx.receipt.Status == 0 {
+					metrics.failedTxs++
+				}
+				if timeToInclude, ok := tx.timeToInclude(blockTimes); ok {
+					metrics.timeToInclude.record(timeToInclude)
+				}
+			case tx.sendFailed():
+				metrics.sendFailedTxs++
+			default:
+				metrics.droppedTxs++
+			}
+		}
+
+		sendersMetrics = append(sendersMetrics, metrics)
+	}
+
+	return sendersMetrics
+}
+
+// senderFairness returns Jain's fairness index of mined share (mined/sent) and of mean mine time of senders with mined txs.
+// The index is 1 when all senders are treated equally and drops as values spread, it reacts to the whole distribution:
+// a single starved sender (mined share 0) of n gives (n-1)/n, a single sender much slower than others tends to 1/n.
+func senderFairness(sendersMetrics []*SenderMetrics) (float64, float64) {
+	minedShares, meanTimes := senderValues(sendersMetrics)
+	return jainIndex(minedShares), jainIndex(meanTimes)
+}
+
+// senderSpread returns the lowest to the highest mined share and the fastest to the slowest mean mine time of senders,
+// so a single starved or delayed sender is visible regardless of the number of senders.
+func senderSpread(sendersMetrics []*SenderMetrics) (float64, float64) {
+	minedShares, meanTimes := senderValues(sendersMetrics)
+	return minMaxRatio(minedShares), minMaxRatio(meanTimes)
+}
+
+// senderValues returns mined shares of senders with sent txs and mean mine times of senders with mined txs.
+func senderValues(sendersMetrics []*SenderMetrics) ([]float64, []float64) {
+	var minedShares, meanTimes []float64
+	for _, metrics := range sendersMetrics {
+		if metrics.sentTxs == 0 {
+			continue
+		}
+		minedShares = append(minedShares, float64(metrics.minedTxs)/float64(metrics.sentTxs))
+		if metrics.timeToInclude.total != 0 {
+			meanTimes = append(meanTimes, metrics.timeToInclude.mean())
+		}
+	}
+
+	return minedShares, meanTimes
+}
+
+// jainIndex returns (sum x)^2 / (n * sum x^2), 1 for equal values and for no values.
+func jainIndex(values []float64) float64 {
+	var sum, sumSquares float64
+	for _, value := range values {
+		sum += value
+		sumSquares += value * value
+	}
+	if sumSquares == 0 {
+		return 1
+	}
+
+	return sum * sum / (float64(len(values)) * sumSquares)
+}
+
+// minMaxRatio returns min/max of values, 1 for equal values and for no values.
+func minMaxRatio(values []float64) float64 {
+	if len(values) == 0 {
+		return 1
+	}
+
+	lowest, highest := values[0], values[0]
+	for _, value := range values {
+		lowest = min(lowest, value)
+		highest = max(highest, value)
+	}
+	if highest == 0 {
+		return 1
+	}
+
+	return lowest / highest
+}
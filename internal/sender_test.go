@@ -0,0 +1,64 @@
+package internal
+
+import (
+	"math"
+	"testing"
+)
+
+func TestJainIndex(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		want   float64
+	}{
+		{"no values", nil, 1},
+		{"all zero", []float64{0, 0}, 1},
+		{"equal", []float64{3, 3, 3}, 1},
+		{"one of two", []float64{1, 0}, 0.5},
+		{"one of four", []float64{0, 0, 5, 0}, 0.25},
+		{"uneven", []float64{1, 2, 3}, 36.0 / 42},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := jainIndex(test.values); math.Abs(got-test.want) > 1e-9 {
+				t.Fatalf("got %f, want %f", got, test.want)
+			}
+		})
+	}
+}
+
+// newTestSenderMetrics returns metrics of a sender with the given mine times in ms.
+func newTestSenderMetrics(sentTxs, minedTxs uint, mineTimes ...int64) *SenderMetrics {
+	metrics := &SenderMetrics{sentTxs: sentTxs, minedTxs: minedTxs, timeToInclude: NewHistogram()}
+	for _, mineTime := range mineTimes {
+		metrics.timeToInclude.record(mineTime)
+	}
+	return metrics
+}
+
+func TestSenderSpread(t *testing.T) {
+	tests := []struct {
+		name         string
+		metrics      []*SenderMetrics
+		wantMined    float64
+		wantMineTime float64
+	}{
+		{"no senders", nil, 1, 1},
+		{"nothing sent", []*SenderMetrics{newTestSenderMetrics(0, 0)}, 1, 1},
+		{"nothing mined", []*SenderMetrics{newTestSenderMetrics(10, 0), newTestSenderMetrics(10, 0)}, 1, 1},
+		{"equal senders", []*SenderMetrics{newTestSenderMetrics(10, 10, 1000), newTestSenderMetrics(20, 20, 1000)}, 1, 1},
+		{"starved sender", []*SenderMetrics{newTestSenderMetrics(10, 10, 1000), newTestSenderMetrics(10, 0)}, 0, 1},
+		{"slow sender", []*SenderMetrics{newTestSenderMetrics(10, 10, 1000), newTestSenderMetrics(10, 5, 4000)}, 0.5, 0.25},
+		{"idle sender skipped", []*SenderMetrics{newTestSenderMetrics(10, 10, 1000, 3000), newTestSenderMetrics(0, 0), newTestSenderMetrics(4, 4, 1000)}, 1, 0.5},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mined, mineTime := senderSpread(test.metrics)
+			if math.Abs(mined-test.wantMined) > 1e-9 || math.Abs(mineTime-test.wantMineTime) > 1e-9 {
+				t.Fatalf("got %f/%f, want %f/%f", mined, mineTime, test.wantMined, test.wantMineTime)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package internal
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// resolveSenderIndices returns explicitly selected sender indices of the test (indices, range or group),
+// nil means test only defines number of senders.
+func resolveSenderIndices(config TestConfig, groups map[string]string, total int) ([]int, error) {
+	var indices []int
+
+	indices = append(indices, config.SenderIndices...)
+
+	if config.SenderRange != "" {
+		rangeIndices, err := parseIndexRanges(config.SenderRange, total)
+		if err != nil {
+			return nil, fmt.Errorf("invalid sender_range: %w", err)
+		}
+		indices = append(indices, rangeIndices...)
+	}
+
+	if config.SenderGroup != "" {
+		group, exists := groups[config.SenderGroup]
+		if !exists {
+			return nil, fmt.Errorf("unknown sender_group: %s", config.SenderGroup)
+		}
+		groupIndices, err := parseIndexRanges(group, total)
+		if err != nil {
+			return nil, fmt.Errorf("invalid sender group '%s': %w", config.SenderGroup, err)
+		}
+		indices = append(indices, groupIndices...)
+	}
+
+	seen := make(map[int]bool, len(indices))
+	for _, index := range indices {
+		if index < 0 || index >= total {
+			return nil, fmt.Errorf("sender index %d is out of range, %d senders are configured", index, total)
+		}
+		if seen[index] {
+			return nil, fmt.Errorf("sender index %d is selected more than once", index)
+		}
+		seen[index] = true
+	}
+
+	return indices, nil
+}
+
+// parseIndexRanges parses comma separated indices and ranges, e.g. "0-3,7,9-10".
+// Indices are checked against the number of senders before a range is expanded.
+func parseIndexRanges(spec string, total int) ([]int, error) {
+	var indices []int
+
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		from, to, isRange := strings.Cut(part, "-")
+		start, err := strconv.Atoi(strings.TrimSpace(from))
+		if err != nil {
+			return nil, fmt.Errorf("invalid index '%s'", part)
+		}
+		end := start
+		if isRange {
+			end, err = strconv.Atoi(strings.TrimSpace(to))
+			if err != nil || end < start {
+				return nil, fmt.Errorf("invalid range '%s'", part)
+			}
+		}
+
+		if end >= total {
+			return nil, fmt.Errorf("sender index %d is out of range, %d senders are configured", end, total)
+		}
+
+		for i := start; i <= end; i++ {
+			indices = append(indices, i)
+		}
+	}
+
+	return indices, nil
+}
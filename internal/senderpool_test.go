@@ -0,0 +1,91 @@
+package internal
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseIndexRanges(t *testing.T) {
+	tests := []struct {
+		name  string
+		spec  string
+		total int
+		want  []int
+		error string
+	}{
+		{"single index", "3", 10, []int{3}, ""},
+		{"ranges and indices", "0-2, 7 ,8-9", 10, []int{0, 1, 2, 7, 8, 9}, ""},
+		{"empty parts", "1,,2,", 10, []int{1, 2}, ""},
+		{"last index", "9", 10, []int{9}, ""},
+		{"index out of range", "10", 10, nil, "sender index 10 is out of range, 10 senders are configured"},
+		{"range out of range", "5-10", 10, nil, "sender index 10 is out of range"},
+		{"huge range", "0-4000000000", 10, nil, "sender index 4000000000 is out of range"},
+		{"reversed range", "5-3", 10, nil, "invalid range '5-3'"},
+		{"negative index", "-1", 10, nil, "invalid index '-1'"},
+		{"not a number", "a", 10, nil, "invalid index 'a'"},
+		{"open range", "3-", 10, nil, "invalid range '3-'"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			indices, err := parseIndexRanges(test.spec, test.total)
+			if test.error != "" {
+				if err == nil || !strings.Contains(err.Error(), test.error) {
+					t.Fatalf("got error %v, want %q", err, test.error)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(indices, test.want) {
+				t.Fatalf("got %v, want %v", indices, test.want)
+			}
+		})
+	}
+}
+
+func TestResolveSenderIndices(t *testing.T) {
+	groups := map[string]string{
+		"whales": "0-1",
+		"broken": "1-x",
+	}
+
+	tests := []struct {
+		name   string
+		config TestConfig
+		want   []int
+		error  string
+	}{
+		{"nothing selected", TestConfig{}, nil, ""},
+		{"indices", TestConfig{SenderIndices: []int{4, 2}}, []int{4, 2}, ""},
+		{"range", TestConfig{SenderRange: "2-4"}, []int{2, 3, 4}, ""},
+		{"group", TestConfig{SenderGroup: "whales"}, []int{0, 1}, ""},
+		{"combined", TestConfig{SenderIndices: []int{5}, SenderRange: "2-3", SenderGroup: "whales"}, []int{5, 2, 3, 0, 1}, ""},
+		{"index out of range", TestConfig{SenderIndices: []int{6}}, nil, "sender index 6 is out of range, 6 senders are configured"},
+		{"negative index", TestConfig{SenderIndices: []int{-1}}, nil, "sender index -1 is out of range"},
+		{"invalid range", TestConfig{SenderRange: "0-9"}, nil, "invalid sender_range: sender index 9 is out of range"},
+		{"duplicate index", TestConfig{SenderIndices: []int{1}, SenderGroup: "whales"}, nil, "sender index 1 is selected more than once"},
+		{"unknown group", TestConfig{SenderGroup: "minnows"}, nil, "unknown sender_group: minnows"},
+		{"invalid group", TestConfig{SenderGroup: "broken"}, nil, "invalid sender group 'broken': invalid range '1-x'"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			indices, err := resolveSenderIndices(test.config, groups, 6)
+			if test.error != "" {
+				if err == nil || !strings.Contains(err.Error(), test.error) {
+					t.Fatalf("got error %v, want %q", err, test.error)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(indices, test.want) {
+				t.Fatalf("got %v, want %v", indices, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	SignMethodEth     = "eth_signTransaction"     // geth, Web3Signer
+	SignMethodAccount = "account_signTransaction" // Clef
+)
+
+// TxSigner signs transactions of a sender.
+type TxSigner interface {
+	SignTx(tx *types.Transaction, from common.Address, chainId *big.Int) (*types.Transaction, error)
+}
+
+// LocalSigner signs transactions with a private key available on the load generator host.
+type LocalSigner struct {
+	privateKey *ecdsa.PrivateKey
+}
+
+// RemoteSigner signs transactions with an external signer via JSON-RPC (Clef, Web3Signer or any node with unlocked accounts).
+type RemoteSigner struct {
+	client *rpc.Client
+	method string
+}
+
+// signTxArgs are transaction arguments of eth_signTransaction / account_signTransaction.
+type signTxArgs struct {
+	From                 common.Address  `json:"from"`
+	To                   *common.Address `json:"to,omitempty"`
+	Gas                  hexutil.Uint64  `json:"gas"`
+	MaxFeePerGas         *hexutil.Big    `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas *hexutil.Big    `json:"maxPriorityFeePerGas"`
+	Value                *hexutil.Big    `json:"value"`
+	Nonce                hexutil.Uint64  `json:"nonce"`
+	Data                 hexutil.Bytes   `json:"data"`
+	ChainID              *hexutil.Big    `json:"chainId"`
+}
+
+func NewLocalSigner(privateKey *ecdsa.PrivateKey) *LocalSigner {
+	return &LocalSigner{privateKey: privateKey}
+}
+
+func (s *LocalSigner) SignTx(tx *types.Transaction, from common.Address, chainId *big.Int) (*types.Transaction, error) {
+	return types.SignTx(tx, types.NewLondonSigner(chainId), s.privateKey)
+}
+
+// NewRemoteSigner connects to the external signer, method is eth_signTransaction by default.
+func NewRemoteSigner(url string, method string) (*RemoteSigner, error) {
+	if method == "" {
+		method = SignMethodEth
+	}
+	if method != SignMethodEth && method != SignMethodAccount {
+		return nil, fmt.Errorf("unknown signer method: %s (expected %s or %s)", method, SignMethodEth, SignMethodAccount)
+	}
+
+	client, err := rpc.Dial(url)
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect to remote signer %s: %w", url, err)
+	}
+
+	return &RemoteSigner{client: client, method: method}, nil
+}
+
+func (s *RemoteSigner) SignTx(tx *types.Transaction, from common.Address, chainId *big.Int) (*types.Transaction, error) {
+	args := signTxArgs{
+		From:                 from,
+		To:                   tx.To(),
+		Gas:                  hexutil.Uint64(tx.Gas()),
+		MaxFeePerGas:         (*hexutil.Big)(tx.GasFeeCap()),
+		MaxPriorityFeePerGas: (*hexutil.Big)(tx.GasTipCap()),
+		Value:                (*hexutil.Big)(tx.Value()),
+		Nonce:                hexutil.Uint64(tx.Nonce()),
+		Data:                 tx.Data(),
+		ChainID:              (*hexutil.Big)(chainId),
+	}
+
+	var result json.RawMessage
+	if err := s.client.CallContext(context.Background(), &result, s.method, args); err != nil {
+		return nil, fmt.Errorf("remote signer %s failed: %w", s.method, err)
+	}
+
+	raw, err := parseSignResult(result)
+	if err != nil {
+		return nil, err
+	}
+
+	signedTx := new(types.Transaction)
+	if err = signedTx.UnmarshalBinary(raw); err != nil {
+		return nil, fmt.Errorf("failed to decode transaction from remote signer: %w", err)
+	}
+
+	if err = checkSignedTx(tx, signedTx, from, chainId); err != nil {
+		return nil, fmt.Errorf("remote signer returned unexpected transaction: %w", err)
+	}
+
+	return signedTx, nil
+}
+
+// checkSignedTx makes sure the signer signed the requested transaction from the sender and didn't change any of its fields.
+func checkSignedTx(tx *types.Transaction, signedTx *types.Transaction, from common.Address, chainId *big.Int) error {
+	signedFrom, err := types.Sender(types.LatestSignerForChainID(chainId), signedTx)
+	if err != nil {
+		return fmt.Errorf("invalid signature: %w", err)
+	}
+
+	switch {
+	case signedFrom != from:
+		return fmt.Errorf("signed by %s, expected %s", signedFrom.Hex(), from.Hex())
+	case signedTx.ChainId().Cmp(chainId) != 0:
+		return fmt.Errorf("chain id %s, expected %s", signedTx.ChainId(), chainId)
+	case signedTx.Type() != tx.Type():
+		return fmt.Errorf("tx type %d, expected %d", signedTx.Type(), tx.Type())
+	case signedTx.Nonce() != tx.Nonce():
+		return fmt.Errorf("nonce %d, expected %d", signedTx.Nonce(), tx.Nonce())
+	case signedTx.Gas() != tx.Gas():
+		return fmt.Errorf("gas %d, expected %d", signedTx.Gas(), tx.Gas())
+	case signedTx.GasFeeCap().Cmp(tx.GasFeeCap()) != 0:
+		return fmt.Errorf("max fee per gas %s, expected %s", signedTx.GasFeeCap(), tx.GasFeeCap())
+	case signedTx.GasTipCap().Cmp(tx.GasTipCap()) != 0:
+		return fmt.Errorf("max priority fee per gas %s, expected %s", signedTx.GasTipCap(), tx.GasTipCap())
+	case (signedTx.To() == nil) != (tx.To() == nil) || (tx.To() != nil && *signedTx.To() != *tx.To()):
+		return fmt.Errorf("to %v, expected %v", signedTx.To(), tx.To())
+	case signedTx.Value().Cmp(tx.Value()) != 0:
+		return fmt.Errorf("value %s, expected %s", signedTx.Value(), tx.Value())
+	case !bytes.Equal(signedTx.Data(), tx.Data()):
+		return fmt.Errorf("data differs from the requested data")
+	}
+
+	return nil
+}
+
+// parseSignResult extracts raw tx from the signer response: {"raw": "0x..", "tx": {..}} (geth, Clef) or "0x.." (Web3Signer).
+func parseSignResult(result json.RawMessage) ([]byte, error) {
+	var raw hexutil.Bytes
+	if err := json.Unmarshal(result, &raw); err == nil {
+		return raw, nil
+	}
+
+	var response struct {
+		Raw hexutil.Bytes `json:"raw"`
+	}
+	if err := json.Unmarshal(result, &response); err != nil || len(response.Raw) == 0 {
+		return nil, fmt.Errorf("unexpected remote signer response: %s", string(result))
+	}
+
+	return response.Raw, nil
+}
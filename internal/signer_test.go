@@ -0,0 +1,149 @@
+package internal
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// newSignerServer starts a JSON-RPC server signing txs with the key, tamper changes the tx before signing.
+func newSignerServer(t *testing.T, key *ecdsa.PrivateKey, tamper func(tx *types.DynamicFeeTx)) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+			Params []signTxArgs    `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil || len(request.Params) != 1 {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+
+		args := request.Params[0]
+		unsigned := &types.DynamicFeeTx{
+			ChainID:   args.ChainID.ToInt(),
+			Nonce:     uint64(args.Nonce),
+			GasTipCap: args.MaxPriorityFeePerGas.ToInt(),
+			GasFeeCap: args.MaxFeePerGas.ToInt(),
+			Gas:       uint64(args.Gas),
+			To:        args.To,
+			Value:     args.Value.ToInt(),
+			Data:      args.Data,
+		}
+		if tamper != nil {
+			tamper(unsigned)
+		}
+
+		signedTx, err := types.SignNewTx(key, types.LatestSignerForChainID(unsigned.ChainID), unsigned)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		raw, _ := signedTx.MarshalBinary()
+
+		// geth and Clef wrap raw tx in an object, Web3Signer returns raw tx only
+		var result any = hexutil.Bytes(raw)
+		if request.Method == SignMethodEth {
+			result = map[string]any{"raw": hexutil.Bytes(raw), "tx": signedTx}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{"jsonrpc": "2.0", "id": request.ID, "result": result})
+	}))
+}
+
+func newUnsignedTestTx() *types.Transaction {
+	to := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	return types.NewTx(&types.DynamicFeeTx{
+		ChainID:   big.NewInt(1337),
+		Nonce:     7,
+		GasTipCap: big.NewInt(2e9),
+		GasFeeCap: big.NewInt(30e9),
+		Gas:       50000,
+		To:        &to,
+		Value:     big.NewInt(1000),
+		Data:      []byte{0xde, 0xad, 0xbe, 0xef},
+	})
+}
+
+func TestRemoteSignerSignTx(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	chainId := big.NewInt(1337)
+
+	for _, method := range []string{SignMethodEth, SignMethodAccount} {
+		t.Run(method, func(t *testing.T) {
+			server := newSignerServer(t, key, nil)
+			defer server.Close()
+
+			signer, err := NewRemoteSigner(server.URL, method)
+			if err != nil {
+				t.Fatalf("failed to create signer: %v", err)
+			}
+
+			tx := newUnsignedTestTx()
+			signedTx, err := signer.SignTx(tx, from, chainId)
+			if err != nil {
+				t.Fatalf("failed to sign tx: %v", err)
+			}
+			if signedTx.Nonce() != tx.Nonce() || *signedTx.To() != *tx.To() || signedTx.Value().Cmp(tx.Value()) != 0 {
+				t.Fatalf("signed tx differs from the requested tx")
+			}
+		})
+	}
+}
+
+func TestRemoteSignerRejectsTamperedTx(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	otherKey, _ := crypto.GenerateKey()
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	attacker := common.HexToAddress("0x00000000000000000000000000000000000000bb")
+
+	tests := []struct {
+		name   string
+		key    *ecdsa.PrivateKey
+		tamper func(tx *types.DynamicFeeTx)
+		error  string
+	}{
+		{"sender", otherKey, nil, "signed by"},
+		{"chain id", key, func(tx *types.DynamicFeeTx) { tx.ChainID = big.NewInt(1) }, ""},
+		{"nonce", key, func(tx *types.DynamicFeeTx) { tx.Nonce++ }, "nonce"},
+		{"gas", key, func(tx *types.DynamicFeeTx) { tx.Gas++ }, "gas"},
+		{"max fee", key, func(tx *types.DynamicFeeTx) { tx.GasFeeCap = big.NewInt(100e9) }, "max fee per gas"},
+		{"max priority fee", key, func(tx *types.DynamicFeeTx) { tx.GasTipCap = big.NewInt(10e9) }, "max priority fee per gas"},
+		{"to", key, func(tx *types.DynamicFeeTx) { tx.To = &attacker }, "to"},
+		{"value", key, func(tx *types.DynamicFeeTx) { tx.Value = big.NewInt(1e18) }, "value"},
+		{"data", key, func(tx *types.DynamicFeeTx) { tx.Data = []byte{0x01} }, "data"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := newSignerServer(t, test.key, test.tamper)
+			defer server.Close()
+
+			signer, err := NewRemoteSigner(server.URL, SignMethodAccount)
+			if err != nil {
+				t.Fatalf("failed to create signer: %v", err)
+			}
+
+			_, err = signer.SignTx(newUnsignedTestTx(), from, big.NewInt(1337))
+			if err == nil {
+				t.Fatalf("tampered tx was accepted")
+			}
+			if !strings.Contains(err.Error(), test.error) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
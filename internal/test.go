@@ -5,64 +5,95 @@ import (
 	"errors"
 	"fmt"
 	"github.com/ethereum/go-ethereum"
-	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/ethclient"
-	"log"
+	"github.com/ethereum/go-ethereum/rpc"
 	"math/big"
 	"sort"
-	"strconv"
-	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 )
 
 type Test struct {
-	client *ethclient.Client
+	client    *RpcClient
+	endpoints *EndpointPool
+	heads     *HeadTracker
 	// config data
-	chainId  int64
-	testName string
-	testType string
-	dataSize int
-	txsCount int
-	duration int
-	tps      int
-	value    *big.Int
+	chainId     int64
+	testName    string
+	testType    string
+	dataSize    int
+	txsCount    int
+	duration    int
+	tps         int
+	value       *big.Int
+	receivers   ReceiversConfig
+	payload     PayloadConfig
+	gas         *GasStrategy
+	replacement ReplacementConfig
+	batch       BatchConfig
+	call        CallConfig
 	// process data
 	senders            []*Sender
 	isContract         bool
 	contract           Contract
 	senderTransactions map[string][]*Transaction
+	resyncs            int32
+	sendErrors         *SendErrors
+	collector          *receiptCollector
 	// metrics data
-	blocks      []*types.Block
-	startBlock  uint64
-	endBlock    uint64
-	metrics     *Metrics
-	callMetrics *CallMetrics
-}
-
-type Contract struct {
-	address      string
-	functionData Function
-}
-type Function struct {
-	name   string
-	abi    string
-	params []interface{} `yaml:"params"`
+	blocks       []*types.Block    // blocks with test txs
+	windowBlocks []*rpcBlockHeader // all blocks of the test window without tx bodies, for the block details report only
+	startBlock   uint64
+	endBlock     uint64
+	metrics      *Metrics
+	callMetrics  *CallMetrics
 }
 
 type Metrics struct {
 	configTps               uint
-	realTps                 uint
-	avgTxsPerBlock          uint
+	realTps                 float64 // mined txs of the test per second
+	sentTps                 float64
+	mgasPerSec              float64         // gas used by mined txs of the test, millions per second
+	avgTxsPerBlock          uint            // txs of the test, over blocks from the first to the last block with its txs
 	avgTxsBlockDiffIncluded map[uint64]uint // in which block tx were included after sent (block_tx_mined - block_tx_were_sent)
-	avgTimeToInclude        uint            //in sec??
+	avgTimeToInclude        uint            // in ms
+	timeToInclude           *Histogram      // in ms, of mined txs
+	blockDistance           *Histogram      // in blocks, of mined txs
+	sendLatency             *Histogram      // in µs, eth_sendRawTransaction round trip of txs and replacements, retry backoff excluded
+	sendErrors              []*SendErrorMetrics
+	senders                 []*SenderMetrics
+	blocks                  []*BlockMetrics
+	minedShareFairness      float64 // Jain's index of mined/sent ratio of senders
+	mineTimeFairness        float64 // Jain's index of mean mine time of senders
+	minedShareSpread        float64 // lowest / highest mined/sent ratio of senders
+	mineTimeSpread          float64 // fastest / slowest mean mine time of senders
 	avgGasPricePerTx        uint
 	avgGasUsedPerBlock      uint
 	succeedTxs              uint
 	failedTxs               uint
+	sendFailedTxs           uint // txs rejected by the node on send
+	gapLostTxs              uint // txs sent, but not mined because of a nonce gap
+	notMinedTxs             uint // txs sent, but not mined for other reasons (dropped or still pending)
+	notSentTxs              uint // txs left unsent after interruption
+	nonceResyncs            uint
+	blockSeries             []*BlockThroughput
+	secondSeries            []*SecondThroughput
+	functions               []*FunctionMetrics
+	replacements            *ReplacementMetrics
+	endpoints               []*EndpointMetrics
+}
+
+// FunctionMetrics contains metrics of a single function from the contract functions mix.
+type FunctionMetrics struct {
+	label            string
+	sentTxs          uint
+	succeedTxs       uint
+	failedTxs        uint
+	avgGasUsed       uint
+	avgTimeToInclude uint // in ms
 }
 
 type CallMetrics struct {
@@ -70,93 +101,130 @@ type CallMetrics struct {
 	callSentCount    int
 	callReceiveCount int
 	callErrorsCount  int
-	errorMessages    []Message
+	invalidResults   int               // results that failed to decode or differ from the expected values
+	errors           map[string]int    // failed calls grouped by message, invalid results grouped by kind
+	errorsFirstSeen  map[string]string // first seen message of each group
+	latency          *Histogram        // in µs
+	startTime        time.Time
+	endTime          time.Time
+	functions        map[string]*FunctionCallMetrics
+	mu               sync.Mutex
 }
 
-type Message struct {
-	Error   bool
-	Message string
+// FunctionCallMetrics contains call results of a single function from the contract functions mix.
+type FunctionCallMetrics struct {
+	calls          int
+	errors         int
+	invalidResults int
+	latency        *Histogram // in µs
 }
 
-func NewTest(client *ethclient.Client, chainId int64, configTestName string, configTest TestEntity) *Test {
+func NewTest(client *RpcClient, endpoints *EndpointPool, heads *HeadTracker, chainId int64, configTestName string, configTest TestEntity) (*Test, error) {
+	contract, err := NewContract(configTest.Config.Contract)
+	if err != nil {
+		return nil, err
+	}
+	if configTest.Type == CALL && !contract.isDefined() {
+		return nil, fmt.Errorf("call test requires contract function")
+	}
+
 	test := &Test{
-		client:   client,
-		chainId:  chainId,
-		testName: configTestName,
-		testType: configTest.Type,
-		txsCount: configTest.Config.TPS * configTest.Config.Duration,
-		dataSize: configTest.Config.DataSize,
-		duration: configTest.Config.Duration,
-		tps:      configTest.Config.TPS,
-		contract: Contract{
-			address: configTest.Config.Contract.Address,
-			functionData: Function{
-				name:   configTest.Config.Contract.Function.Name,
-				abi:    configTest.Config.Contract.Function.ABI,
-				params: configTest.Config.Contract.Function.Params,
-			},
-		},
-		isContract: configTest.Config.Contract.Address != "" &&
-			configTest.Config.Contract.Function.Name != "" &&
-			configTest.Config.Contract.Function.ABI != "",
-	}
-
-	if configTest.Config.value == "" {
+		client:      client,
+		endpoints:   endpoints,
+		heads:       heads,
+		chainId:     chainId,
+		testName:    configTestName,
+		testType:    configTest.Type,
+		txsCount:    configTest.Config.TPS * configTest.Config.Duration,
+		dataSize:    configTest.Config.DataSize,
+		duration:    configTest.Config.Duration,
+		tps:         configTest.Config.TPS,
+		receivers:   configTest.Config.Receivers,
+		payload:     configTest.Config.Payload,
+		gas:         NewGasStrategy(configTest.Config.Gas),
+		replacement: configTest.Config.Replacement,
+		batch:       configTest.Config.Batch,
+		call:        configTest.Config.Call,
+		contract:    contract,
+		sendErrors:  NewSendErrors(),
+	}
+	test.isContract = test.contract.isDefined()
+
+	if configTest.Config.Value == "" {
 		test.value = big.NewInt(0)
 	} else {
 		var err bool
-		test.value, err = big.NewInt(0).SetString(configTest.Config.value, 10)
+		test.value, err = big.NewInt(0).SetString(configTest.Config.Value, 10)
 		if !err {
 			fmt.Printf("failed to parse value for test '%s': using default value 0 \n", configTestName)
 			test.value = big.NewInt(0)
 		}
 	}
 
-	return test
+	return test, nil
 }
 
-func (t *Test) SignTransactions() error {
+func (t *Test) SignTransactions(ctx context.Context) error {
 	txPerSender := t.txsCount / len(t.senders)
-	var data []byte
 
 	if t.isContract {
-		parsedABI, err := abi.JSON(strings.NewReader(t.contract.functionData.abi))
-		if err != nil {
-			log.Fatalf("failed to parse contract ABI: %v", err)
+		if err := t.contract.prepare(); err != nil {
+			return err
 		}
+	}
 
-		var abiMethod abi.Method
-		var exists bool
-		if abiMethod, exists = parsedABI.Methods[t.contract.functionData.name]; !exists {
-			return fmt.Errorf("invalid method name: %s (ensure the method exists in the contract ABI)", t.contract.functionData.name)
-		}
+	receivers, err := newReceiverPicker(t.receivers, t.senders, t.value)
+	if err != nil {
+		return err
+	}
 
-		convertedParams, err := t.convertParams(abiMethod, t.contract.functionData.params)
-		if err != nil {
-			log.Fatalf("failed to convert function parameters: %v", err)
-		}
+	payload, err := newPayloadGenerator(t.payload, t.dataSize)
+	if err != nil {
+		return err
+	}
 
-		data, err = parsedABI.Pack(t.contract.functionData.name, convertedParams...)
-		if err != nil {
-			log.Fatalf("failed to pack ABI data: %v", err)
-		}
-	} else if t.dataSize != 0 {
-		data = t.generateData(t.dataSize)
+	if err = t.gas.prepareFees(ctx, t.client); err != nil {
+		return fmt.Errorf("failed to prepare fees: %w", err)
 	}
 
 	for _, sender := range t.senders {
-		var receiver common.Address
-		if t.isContract {
-			receiver = common.HexToAddress(t.contract.address)
-		} else {
-			receiver = *sender.Address
-		}
-
 		for j := 0; j < txPerSender; j++ {
-			signedTx, err := CreateAndSignTransaction(t.client, t.chainId, sender, &receiver, t.value, data)
+			if err = ctx.Err(); err != nil {
+				return err
+			}
+
+			var function *Function
+			var receiver common.Address
+			var txData []byte
+			gasKey := TransferGasKey
+			if t.isContract {
+				function = t.contract.pick()
+				receiver = function.contractAddress()
+				txData = function.data
+				gasKey = function.label
+			} else {
+				receiver, err = receivers.pick(sender)
+				if err != nil {
+					return err
+				}
+				txData, err = payload.next()
+				if err != nil {
+					return err
+				}
+			}
+
+			signedTx, err := CreateAndSignTransaction(ctx, t.client, t.chainId, sender, &receiver, t.value, txData, t.gas, gasKey)
 			if err != nil {
 				return fmt.Errorf("failed to sign transaction: %w", err)
 			}
+			signedTx.function = function
+
+			if t.shouldReplace() {
+				signedTx.replacement, err = t.signReplacement(sender, signedTx.clientTransaction)
+				if err != nil {
+					return err
+				}
+			}
 
 			t.senderTransactions[sender.Address.String()] =
 				append(t.senderTransactions[sender.Address.String()], signedTx)
@@ -166,7 +234,8 @@ func (t *Test) SignTransactions() error {
 	return nil
 }
 
-func (t *Test) Run() error {
+// Run sends txs (or calls) of the test, sending stops when ctx is canceled, txs left unsent have zero sentTimestamp.
+func (t *Test) Run(ctx context.Context) error {
 	fmt.Printf("Run Test: %s \n", t.testName)
 
 	interval := time.Second / time.Duration(t.tps)
@@ -174,131 +243,270 @@ func (t *Test) Run() error {
 	defer ticker.Stop()
 
 	var wg sync.WaitGroup
+	var replacementsWg sync.WaitGroup
+
+	if t.testType == CALL {
+		t.callMetrics = &CallMetrics{
+			configTps:       uint(t.tps),
+			errors:          make(map[string]int),
+			errorsFirstSeen: make(map[string]string),
+			latency:         NewHistogram(),
+			functions:       make(map[string]*FunctionCallMetrics),
+		}
+	}
 
-	blockNumber, _ := t.client.BlockNumber(context.Background())
-	t.startBlock = blockNumber
-	for _, sender := range t.senders {
-		wg.Add(1)
+	// batchers are shared by senders, sends are batched per endpoint
+	batchers := make(map[*Endpoint]*rpcBatcher)
+	if t.batch.isEnabled() {
 		if t.testType == SEND {
-			go t.runSend(&wg, sender, ticker)
+			for _, endpoint := range t.endpoints.endpoints {
+				batchers[endpoint] = newRPCBatcher(endpoint.client, t.batch)
+			}
 		} else if t.testType == CALL {
-			callMsg, err := t.getCallMsg()
+			batchers[nil] = newRPCBatcher(t.client, t.batch)
+		}
+	}
 
-			if err != nil {
-				fmt.Printf("Contract call message generation error: %s", err.Error())
-				continue
-			}
+	blockNumber, _ := t.client.BlockNumber(ctx)
+	t.startBlock = blockNumber
+	if t.testType == SEND {
+		// receipts are collected while sending, until CollectData
+		t.collector = newReceiptCollector(t, t.startBlock)
+		t.collector.start(ctx)
+	}
 
-			t.callMetrics = &CallMetrics{
-				configTps: uint(t.tps),
+	if t.testType == CALL {
+		t.callMetrics.startTime = time.Now()
+	}
+	for i, sender := range t.senders {
+		wg.Add(1)
+		if t.testType == SEND {
+			go t.runSend(ctx, &wg, &replacementsWg, batchers, sender, ticker)
+		} else if t.testType == CALL {
+			// calls are split evenly, the first senders make the remainder
+			calls := t.txsCount / len(t.senders)
+			if i < t.txsCount%len(t.senders) {
+				calls++
 			}
-
-			go t.runCall(&wg, callMsg, ticker)
+			go t.runCall(ctx, &wg, batchers[nil], sender, calls, ticker)
 		}
 	}
 	wg.Wait()
+	// results of batched requests are delivered after the last batch is flushed
+	for _, batcher := range batchers {
+		batcher.close()
+	}
+	replacementsWg.Wait()
+	if t.testType == CALL {
+		t.callMetrics.endTime = time.Now()
+	}
 
-	blockNumber, _ = t.client.BlockNumber(context.Background())
-	t.endBlock = blockNumber
+	// tracked head, node may not be asked anymore when ctx is canceled
+	t.endBlock = t.heads.Current()
 
 	return nil
 }
 
-func (t *Test) runSend(wg *sync.WaitGroup, sender *Sender, ticker *time.Ticker) {
+func (t *Test) runSend(ctx context.Context, wg *sync.WaitGroup, replacementsWg *sync.WaitGroup, batchers map[*Endpoint]*rpcBatcher, sender *Sender, ticker *time.Ticker) {
 	defer wg.Done()
 	senderAddress := sender.Address.String()
-	for i, txSigned := range t.senderTransactions[senderAddress] {
-		//get block before send TX
-		blockNumber, _ := t.client.BlockNumber(context.Background())
-		txSigned.sentBlock = blockNumber
+	senderTxs := t.senderTransactions[senderAddress]
+	// batched sends of the sender without result yet
+	var inflight sync.WaitGroup
+	for i, txSigned := range senderTxs {
+		if ctx.Err() != nil {
+			break
+		}
+
+		// previous send failed (in this or previous test), re-sign the rest of the queue to fill the nonce gap
+		if sender.needsResync.Load() {
+			// txs sent after the failed one may still be in flight, their nonces are known only after their results
+			for _, batcher := range batchers {
+				batcher.flush()
+			}
+			inflight.Wait()
+			if err := t.resyncSender(ctx, sender, senderTxs[:i], senderTxs[i:]); err != nil {
+				fmt.Printf("failed to re-sync sender nonce: %v \n", err)
+			}
+		}
+
+		// latest block before send TX
+		txSigned.sentBlock = t.heads.Current()
 		txSigned.sentTimestamp = time.Now().UnixMilli()
-		t.senderTransactions[senderAddress][i] = txSigned
 
 		// send TX to RPC
-		err := t.client.SendTransaction(context.Background(), txSigned.clientTransaction)
-		if err != nil {
-			fmt.Printf("failed to send transaction: %v", err)
+		txSigned.endpoint = t.endpoints.pick(sender)
+		t.collector.track(txSigned, txSigned.clientTransaction.Hash())
+		if batcher, exists := batchers[txSigned.endpoint]; exists {
+			// send error marks the sender for re-sync when the batch result arrives, txs sent meanwhile keep their nonces
+			rawTx, err := txSigned.clientTransaction.MarshalBinary()
+			if err != nil {
+				t.onTransactionSent(ctx, replacementsWg, sender, txSigned, err, 0)
+			} else {
+				inflight.Add(1)
+				batcher.submit("eth_sendRawTransaction", new(common.Hash), func(err error, latency time.Duration) {
+					defer inflight.Done()
+					t.onTransactionSent(ctx, replacementsWg, sender, txSigned, err, latency)
+				}, hexutil.Encode(rawTx))
+			}
+		} else {
+			// in-flight send isn't canceled, so the send result of every tx is known
+			latency, err := txSigned.endpoint.client.sendTransactionTimed(context.WithoutCancel(ctx), txSigned.clientTransaction)
+			t.onTransactionSent(ctx, replacementsWg, sender, txSigned, err, latency)
 		}
 
-		<-ticker.C
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+		}
+	}
+
+	// txs of the sender in the next tests were signed with previous nonces
+	if sender.nonceChanged {
+		sender.nonceChanged = false
+		sender.needsResync.Store(true)
 	}
 }
 
-func (t *Test) runCall(wg *sync.WaitGroup, callMsg *ethereum.CallMsg, ticker *time.Ticker) {
+// onTransactionSent records the send result of the tx and launches its replacement.
+// Batched results arrive on the batcher goroutine, send result is guarded by the collector mutex.
+func (t *Test) onTransactionSent(ctx context.Context, replacementsWg *sync.WaitGroup, sender *Sender, tx *Transaction, err error, latency time.Duration) {
+	t.collector.mu.Lock()
+	tx.sendLatency = latency
+	if err != nil {
+		t.handleSendError(sender, tx, err)
+	}
+	accepted := tx.sendErr == nil
+	t.collector.mu.Unlock()
+
+	if accepted && tx.replacement != nil {
+		replacementsWg.Add(1)
+		go t.sendReplacement(ctx, replacementsWg, tx)
+	}
+}
+
+// runCall sends the sender's share of calls, senders share the ticker, so all calls together keep the test TPS.
+func (t *Test) runCall(ctx context.Context, wg *sync.WaitGroup, batcher *rpcBatcher, sender *Sender, calls int, ticker *time.Ticker) {
 	defer wg.Done()
-	for i := 0; i < t.txsCount; i++ {
+	for i := 0; i < calls && ctx.Err() == nil; i++ {
+		function := t.contract.pick()
+		contractAddr := function.contractAddress()
+		callMsg := ethereum.CallMsg{
+			To:   &contractAddr,
+			Data: function.data,
+		}
+		if t.call.FromSender {
+			callMsg.From = *sender.Address
+		}
+
 		// call contract
-		_, err := t.client.CallContract(context.Background(), *callMsg, nil)
-		if err != nil {
-			fmt.Printf("contract call failed: %v", err)
-			t.callMetrics.callErrorsCount++
-			t.callMetrics.errorMessages = append(t.callMetrics.errorMessages, Message{Error: true, Message: err.Error()})
+		if batcher != nil {
+			callArgs := map[string]interface{}{
+				"to":   callMsg.To,
+				"data": hexutil.Bytes(callMsg.Data),
+			}
+			if t.call.FromSender {
+				callArgs["from"] = callMsg.From
+			}
+			result := new(hexutil.Bytes)
+			batcher.submit("eth_call", result, func(err error, latency time.Duration) {
+				t.callMetrics.addFunctionCall(function, latency, *result, err)
+			}, callArgs, "latest")
 		} else {
-			t.callMetrics.callReceiveCount++
+			callStart := time.Now()
+			result, err := t.client.CallContract(ctx, callMsg, nil)
+			t.callMetrics.addFunctionCall(function, time.Since(callStart), result, err)
 		}
-		t.callMetrics.callSentCount++
 
-		<-ticker.C
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+		}
 	}
 }
 
-func (t *Test) CollectData(totalCollectedTxCount *int32) error {
+// CollectData waits for receipts of txs still in flight and fetches blocks of sent txs, collection stops early when ctx is canceled.
+// Receipts are matched block by block since the test start, txs not found in blocks are polled per tx.
+func (t *Test) CollectData(ctx context.Context) error {
+	// collector is created when the test starts, a test that didn't run has nothing to collect
+	if t.collector == nil {
+		return nil
+	}
+	t.collector.finish(ctx)
+
 	// RpcCallPerSecond are common for test and divided by number of senders, each sender sends same amount of transactions
 	interval := time.Second / time.Duration(RpcCallPerSecond)
 	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
 	var wg sync.WaitGroup
-	var mu sync.Mutex
-	blocks := make(map[string]bool)
 
 	for _, sender := range t.senders {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			senderAddress := sender.Address.String()
-			totalTxCount := len(t.senderTransactions[senderAddress])
-			collectedTxCount := 0
-			txCollected := false
-			attempts := 0
-			for attempts < AttemptsToCollect && !txCollected {
-				for txIndex := range t.senderTransactions[senderAddress] {
-					txSent := t.senderTransactions[senderAddress][txIndex]
-					if txSent.receipt != nil {
-						continue
+			senderTxs := t.senderTransactions[sender.Address.String()]
+			for attempts := 0; attempts < LeftoverAttemptsToCollect && ctx.Err() == nil; attempts++ {
+				leftovers := 0
+				for _, txSent := range senderTxs {
+					if txSent.awaitsReceipt() {
+						leftovers++
 					}
+				}
+				if leftovers == 0 {
+					return
+				}
+				if attempts != 0 {
+					t.heads.WaitNewHead(ctx, time.Second*time.Duration(AttemptsToCollectIntervalSec))
+				}
 
-					txReceipt, err := t.client.TransactionReceipt(context.Background(), txSent.clientTransaction.Hash())
-					if err != nil {
+				if t.batch.isEnabled() {
+					t.collectReceiptsBatched(ctx, senderTxs, ticker, t.collector.setReceipt)
+				}
+
+				for _, txSent := range senderTxs {
+					if ctx.Err() != nil {
+						break
+					}
+					if !txSent.awaitsReceipt() {
+						// tx is collected, wasn't accepted by the node or wasn't sent
+						continue
+					}
+					if t.batch.isEnabled() {
 						fmt.Printf("transaction not mined yet (attempt %d): txHash=%s \n", attempts+1, txSent.clientTransaction.Hash())
 						continue
 					}
 
-					t.senderTransactions[senderAddress][txIndex].receipt = txReceipt
-					atomic.AddInt32(totalCollectedTxCount, 1)
-
-					mu.Lock()
-					if _, exists := blocks[txReceipt.BlockHash.String()]; !exists {
-						blocks[txReceipt.BlockHash.String()] = true
+					var txReceipt *types.Receipt
+					var err error
+					for _, txHash := range txSent.hashes() {
+						txReceipt, err = t.client.TransactionReceipt(ctx, txHash)
+						if err == nil {
+							break
+						}
 					}
-					mu.Unlock()
-
-					collectedTxCount++
 					<-ticker.C
-				}
+					if err != nil {
+						fmt.Printf("transaction not mined yet (attempt %d): txHash=%s \n", attempts+1, txSent.clientTransaction.Hash())
+						continue
+					}
 
-				if collectedTxCount == totalTxCount {
-					txCollected = true
+					t.collector.setReceipt(txSent, txReceipt)
 				}
-				attempts++
-				time.Sleep(time.Second * time.Duration(AttemptsToCollectIntervalSec))
 			}
 		}()
 	}
 	wg.Wait()
 
-	for blockHash := range blocks {
-		block, err := t.client.BlockByHash(context.Background(), common.HexToHash(blockHash))
-		if err != nil {
-			log.Fatalf("failed to fetch block by hash: %v", err)
+	// block metrics are partial when blocks can't be fetched, receipts are still reported
+	var blocksErr error
+	for blockHash, block := range t.collector.blocks {
+		if block == nil {
+			var err error
+			block, err = t.client.BlockByHash(ctx, blockHash)
+			if err != nil {
+				blocksErr = fmt.Errorf("test '%s': failed to fetch block by hash: %w", t.testName, err)
+				break
+			}
 		}
 
 		t.blocks = append(t.blocks, block)
@@ -308,7 +516,51 @@ func (t *Test) CollectData(totalCollectedTxCount *int32) error {
 		return t.blocks[i].Number().Cmp(t.blocks[j].Number()) < 0
 	})
 
-	return nil
+	if blocksErr == nil {
+		blocksErr = t.fetchWindowBlocks(ctx)
+	}
+
+	if ctx.Err() == nil {
+		t.markGapLostTxs(ctx)
+	}
+
+	return blocksErr
+}
+
+// collectReceiptsBatched requests receipts of all hashes of not yet mined txs in JSON-RPC batches.
+// Every batch waits for one tick of the shared ticker, so batches are rate limited like single receipt requests.
+func (t *Test) collectReceiptsBatched(ctx context.Context, txs []*Transaction, ticker *time.Ticker, setReceipt func(*Transaction, *types.Receipt)) {
+	var elems []rpc.BatchElem
+	var elemTxs []*Transaction
+	for _, tx := range txs {
+		if !tx.awaitsReceipt() {
+			continue
+		}
+		for _, txHash := range tx.hashes() {
+			elems = append(elems, rpc.BatchElem{
+				Method: "eth_getTransactionReceipt",
+				Args:   []interface{}{txHash},
+				Result: new(*types.Receipt),
+			})
+			elemTxs = append(elemTxs, tx)
+		}
+	}
+
+	for start := 0; start < len(elems) && ctx.Err() == nil; start += t.batch.Size {
+		end := min(start+t.batch.Size, len(elems))
+		if err := t.client.BatchCallContext(ctx, elems[start:end]); err != nil {
+			fmt.Printf("failed to fetch receipts batch: %v \n", err)
+		}
+		<-ticker.C
+	}
+
+	for i, elem := range elems {
+		txReceipt := *elem.Result.(**types.Receipt)
+		if elem.Error != nil || txReceipt == nil || elemTxs[i].receipt != nil {
+			continue
+		}
+		setReceipt(elemTxs[i], txReceipt)
+	}
 }
 
 func (t *Test) CollectMetrics() error {
@@ -317,35 +569,56 @@ func (t *Test) CollectMetrics() error {
 		succeedTxs:              0,
 		failedTxs:               0,
 		avgTxsBlockDiffIncluded: make(map[uint64]uint),
+		timeToInclude:           NewHistogram(),
+		blockDistance:           NewHistogram(),
+		sendLatency:             NewHistogram(),
 	}
 
-	txCount := 0
 	var gasUsed uint = 0
 	for _, block := range t.blocks {
-		txCount += block.Transactions().Len()
 		gasUsed += uint(block.GasUsed())
 	}
 
 	if len(t.blocks) != 0 {
-		metrics.avgTxsPerBlock = uint(txCount / len(t.blocks))
 		metrics.avgGasUsedPerBlock = gasUsed / uint(len(t.blocks))
 	}
 
 	totalGasPrice := big.NewInt(0)
 	var totalTxCount int64 = 0
-	var totalTimeToInclude uint64 = 0
+	blockTimes := t.blockTimes()
 
 	for _, senderTxs := range t.senderTransactions {
 		for _, tx := range senderTxs {
-			metrics.avgTxsBlockDiffIncluded[tx.receipt.BlockNumber.Uint64()-tx.sentBlock]++
+			// zero latency means the tx failed before reaching the node
+			if tx.sendLatency != 0 {
+				metrics.sendLatency.record(tx.sendLatency.Microseconds())
+			}
+			if tx.replacement != nil && tx.replacement.sent {
+				metrics.sendLatency.record(tx.replacement.sendLatency.Microseconds())
+			}
+
+			if tx.receipt == nil {
+				switch {
+				case tx.sentTimestamp == 0:
+					metrics.notSentTxs++
+				case tx.sendFailed():
+					metrics.sendFailedTxs++
+				case tx.gapLost:
+					metrics.gapLostTxs++
+				default:
+					metrics.notMinedTxs++
+				}
+				continue
+			}
+
+			// after a reorg to a shorter chain the tx may be mined below the head it was sent at
+			blockDistance := tx.receipt.BlockNumber.Uint64() - min(tx.sentBlock, tx.receipt.BlockNumber.Uint64())
+			metrics.avgTxsBlockDiffIncluded[blockDistance]++
+			metrics.blockDistance.record(int64(blockDistance))
 			// avgFeePerTx
 			totalGasPrice = totalGasPrice.Add(totalGasPrice, tx.receipt.EffectiveGasPrice)
-			// avgTimeToInclude
-			for _, block := range t.blocks {
-				if block.NumberU64() == tx.receipt.BlockNumber.Uint64() {
-					timeDiff := time.Unix(int64(block.Time()), 0).Sub(time.UnixMilli(tx.sentTimestamp))
-					totalTimeToInclude += uint64(timeDiff.Milliseconds())
-				}
+			if timeToInclude, ok := tx.timeToInclude(blockTimes); ok {
+				metrics.timeToInclude.record(timeToInclude)
 			}
 
 			if tx.receipt.Status == 0 {
@@ -359,109 +632,144 @@ func (t *Test) CollectMetrics() error {
 	}
 
 	if totalTxCount != 0 {
-		metrics.avgTimeToInclude = uint(totalTimeToInclude / uint64(totalTxCount))
+		metrics.avgTimeToInclude = uint(metrics.timeToInclude.mean())
 		metrics.avgGasPricePerTx = uint(totalGasPrice.Div(totalGasPrice, big.NewInt(totalTxCount)).Uint64())
 	}
 
+	metrics.nonceResyncs = uint(atomic.LoadInt32(&t.resyncs))
+	metrics.sendErrors = t.sendErrors.metrics()
+
+	t.collectThroughputMetrics(metrics, blockTimes)
+	metrics.senders = t.collectSenderMetrics(blockTimes)
+	metrics.blocks = t.collectBlockMetrics()
+	metrics.minedShareFairness, metrics.mineTimeFairness = senderFairness(metrics.senders)
+	metrics.minedShareSpread, metrics.mineTimeSpread = senderSpread(metrics.senders)
+	if t.isContract {
+		metrics.functions = t.collectFunctionMetrics(blockTimes)
+	}
+	if t.replacement.Fraction > 0 {
+		metrics.replacements = t.collectReplacementMetrics(blockTimes)
+	}
+	if len(t.endpoints.endpoints) > 1 {
+		metrics.endpoints = t.collectEndpointMetrics(blockTimes)
+	}
+
 	t.metrics = metrics
 
 	return nil
 }
 
-func (t *Test) getCallMsg() (*ethereum.CallMsg, error) {
-	parsedABI, err := abi.JSON(strings.NewReader(t.contract.functionData.abi))
-	if err != nil {
-		log.Fatalf("failed to parse contract ABI (for callMsg): %v", err)
-	}
-
-	var abiMethod abi.Method
-	var exists bool
-	if abiMethod, exists = parsedABI.Methods[t.contract.functionData.name]; !exists {
-		return nil, errors.New(fmt.Sprintf("Method Has Incorrect Name, name: %s \n", t.contract.functionData.name))
-	}
+// collectFunctionMetrics breaks down success rate, gas used and inclusion time by contract function.
+func (t *Test) collectFunctionMetrics(blockTimes map[uint64]uint64) []*FunctionMetrics {
+	functionsMetrics := make([]*FunctionMetrics, 0, len(t.contract.functions))
+	for _, function := range t.contract.functions {
+		functionMetrics := &FunctionMetrics{label: function.label}
+		var gasUsed, timeToInclude uint64
+		var minedTxs uint64
+
+		for _, senderTxs := range t.senderTransactions {
+			for _, tx := range senderTxs {
+				if tx.function != function || tx.sentTimestamp == 0 {
+					continue
+				}
 
-	convertedParams, err := t.convertParams(abiMethod, t.contract.functionData.params)
-	if err != nil {
-		log.Fatalf("failed to convert function parameters: %v", err)
-	}
+				functionMetrics.sentTxs++
+				if tx.receipt == nil {
+					continue
+				}
 
-	data, err := parsedABI.Pack(t.contract.functionData.name, convertedParams...)
-	if err != nil {
-		log.Fatalf("failed to pack ABI data: %v", err)
-	}
+				if tx.receipt.Status == 0 {
+					functionMetrics.failedTxs++
+				} else {
+					functionMetrics.succeedTxs++
+				}
 
-	contractAddr := common.HexToAddress(t.contract.address)
+				minedTxs++
+				gasUsed += tx.receipt.GasUsed
+				if txTimeToInclude, ok := tx.timeToInclude(blockTimes); ok {
+					timeToInclude += uint64(txTimeToInclude)
+				}
+			}
+		}
 
-	return &ethereum.CallMsg{
-		To:   &contractAddr,
-		Data: data,
-	}, nil
-}
+		if minedTxs != 0 {
+			functionMetrics.avgGasUsed = uint(gasUsed / minedTxs)
+			functionMetrics.avgTimeToInclude = uint(timeToInclude / minedTxs)
+		}
 
-func (t *Test) convertParams(method abi.Method, params []interface{}) ([]interface{}, error) {
-	if len(method.Inputs) != len(params) {
-		return nil, fmt.Errorf("parameter count mismatch: expected %d, got %d", len(method.Inputs), len(params))
+		functionsMetrics = append(functionsMetrics, functionMetrics)
 	}
 
-	convertedParams := make([]interface{}, len(params))
-
-	for i, input := range method.Inputs {
-		param := params[i]
+	return functionsMetrics
+}
 
-		switch input.Type.T {
-		case abi.AddressTy:
-			if str, ok := param.(string); ok {
-				convertedParams[i] = common.HexToAddress(str)
-			} else {
-				return nil, fmt.Errorf("parameter %d must be a valid Ethereum address (hex string)", i)
-			}
+// blockTimes maps numbers of collected blocks to their timestamps.
+func (t *Test) blockTimes() map[uint64]uint64 {
+	blockTimes := make(map[uint64]uint64, len(t.blocks))
+	for _, block := range t.blocks {
+		blockTimes[block.NumberU64()] = block.Time()
+	}
 
-		case abi.UintTy, abi.IntTy:
-			if str, ok := param.(string); ok {
-				value, success := new(big.Int).SetString(str, 10)
-				if !success {
-					return nil, fmt.Errorf("invalid uint256 value for parameter %d: %s", i, str)
-				}
-				convertedParams[i] = value
-			} else {
-				return nil, fmt.Errorf("parameter %d has an incorrect type or format", i)
-			}
+	return blockTimes
+}
 
-		case abi.BoolTy:
-			if str, ok := param.(string); ok {
-				value, err := strconv.ParseBool(str)
-				if err != nil {
-					return nil, fmt.Errorf("parameter %d must be a boolean (true/false or 'true'/'false')", i)
-				}
-				convertedParams[i] = value
-			} else if b, ok := param.(bool); ok {
-				convertedParams[i] = b
-			} else {
-				return nil, fmt.Errorf("parameter %d should be a bool", i)
-			}
+// addFunctionCall records the result of a single call of the contract function, the result is checked against the function ABI.
+func (c *CallMetrics) addFunctionCall(function *Function, latency time.Duration, result []byte, err error) {
+	var resultErr error
+	if err == nil {
+		resultErr = function.checkResult(result)
+	}
 
-		case abi.StringTy:
-			if str, ok := param.(string); ok {
-				convertedParams[i] = str
-			} else {
-				return nil, fmt.Errorf("parameter %d must be a string", i)
-			}
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
-		default:
-			return nil, fmt.Errorf("unsupported parameter type for parameter %d: %s", i, input.Type.String())
+	c.callSentCount++
+	if err != nil {
+		c.callErrorsCount++
+		c.addError(err.Error(), err)
+	} else {
+		c.callReceiveCount++
+		c.latency.record(latency.Microseconds())
+	}
+	if resultErr != nil {
+		c.invalidResults++
+		if errors.Is(resultErr, UndecodableResult) {
+			c.addError(UndecodableResult.Error(), resultErr)
+		} else {
+			c.addError(UnexpectedResult.Error(), resultErr)
 		}
 	}
 
-	return convertedParams, nil
-}
+	functionMetrics, exists := c.functions[function.label]
+	if !exists {
+		functionMetrics = &FunctionCallMetrics{latency: NewHistogram()}
+		c.functions[function.label] = functionMetrics
+	}
 
-// generateData create payload
-func (t *Test) generateData(sizeInBytes int) []byte {
-	data := make([]byte, sizeInBytes)
+	functionMetrics.calls++
+	if err != nil {
+		functionMetrics.errors++
+	} else {
+		functionMetrics.latency.record(latency.Microseconds())
+	}
+	if resultErr != nil {
+		functionMetrics.invalidResults++
+	}
+}
 
-	for i := range data {
-		data[i] = 0x41 // 'A'
+// addError counts the error in the group and keeps the first seen message of the group, c.mu must be held.
+func (c *CallMetrics) addError(group string, err error) {
+	c.errors[group]++
+	if _, exists := c.errorsFirstSeen[group]; !exists {
+		c.errorsFirstSeen[group] = err.Error()
 	}
+}
 
-	return data
+// callsPerSecond returns achieved rate of calls, including failed ones.
+func (c *CallMetrics) callsPerSecond() float64 {
+	duration := c.endTime.Sub(c.startTime).Seconds()
+	if duration <= 0 {
+		return 0
+	}
+	return float64(c.callSentCount) / duration
 }
@@ -0,0 +1,111 @@
+package internal
+
+import "math"
+
+// BlockThroughput contains txs of the test sent while the block was the head and txs mined in the block.
+type BlockThroughput struct {
+	number    uint64
+	timestamp uint64 // 0 when the block wasn't fetched (no txs of the test in it)
+	sentTxs   uint
+	minedTxs  uint
+	gasUsed   uint64 // by txs of the test
+}
+
+// SecondThroughput contains txs of the test sent and mined (by block timestamp) within the unix second.
+type SecondThroughput struct {
+	second   int64
+	sentTxs  uint
+	minedTxs uint
+	gasUsed  uint64
+}
+
+// collectThroughputMetrics computes sent and mined TPS, gas throughput and time series of the test's own txs,
+// txs of other users in the same blocks aren't counted.
+// Mined TPS and Mgas/s are txs mined after the first block with txs of the test over the time until the last one.
+func (t *Test) collectThroughputMetrics(metrics *Metrics, blockTimes map[uint64]uint64) {
+	blocks := make(map[uint64]*BlockThroughput)
+	seconds := make(map[int64]*SecondThroughput)
+	block := func(number uint64) *BlockThroughput {
+		if _, exists := blocks[number]; !exists {
+			blocks[number] = &BlockThroughput{number: number, timestamp: blockTimes[number]}
+		}
+		return blocks[number]
+	}
+	second := func(unix int64) *SecondThroughput {
+		if _, exists := seconds[unix]; !exists {
+			seconds[unix] = &SecondThroughput{second: unix}
+		}
+		return seconds[unix]
+	}
+
+	var sentTxs, minedTxs uint
+	var gasUsed uint64
+	var firstSent, lastSent int64 = math.MaxInt64, 0
+	var firstBlock, lastBlock uint64 = math.MaxUint64, 0
+
+	for _, senderTxs := range t.senderTransactions {
+		for _, tx := range senderTxs {
+			if tx.sentTimestamp == 0 {
+				continue
+			}
+
+			sentTxs++
+			firstSent = min(firstSent, tx.sentTimestamp)
+			lastSent = max(lastSent, tx.sentTimestamp)
+			block(tx.sentBlock).sentTxs++
+			second(tx.sentTimestamp/1000).sentTxs++
+
+			if tx.receipt == nil {
+				continue
+			}
+
+			number := tx.receipt.BlockNumber.Uint64()
+			minedTxs++
+			gasUsed += tx.receipt.GasUsed
+			firstBlock = min(firstBlock, number)
+			lastBlock = max(lastBlock, number)
+
+			minedBlock := block(number)
+			minedBlock.minedTxs++
+			minedBlock.gasUsed += tx.receipt.GasUsed
+			if blockTime, exists := blockTimes[number]; exists {
+				minedSecond := second(int64(blockTime))
+				minedSecond.minedTxs++
+				minedSecond.gasUsed += tx.receipt.GasUsed
+			}
+		}
+	}
+
+	if sentTxs > 1 && lastSent > firstSent {
+		metrics.sentTps = float64(sentTxs) / (float64(lastSent-firstSent) / 1000)
+	}
+	if minedTxs != 0 {
+		metrics.avgTxsPerBlock = minedTxs / uint(lastBlock-firstBlock+1)
+		// block timestamps have seconds precision, txs of a single block have no measurable duration.
+		// The first block closes the interval before it, so its txs aren't counted within the duration.
+		if duration := blockTimes[lastBlock] - blockTimes[firstBlock]; blockTimes[firstBlock] != 0 && duration > 0 {
+			metrics.realTps = float64(minedTxs-blocks[firstBlock].minedTxs) / float64(duration)
+			metrics.mgasPerSec = float64(gasUsed-blocks[firstBlock].gasUsed) / float64(duration) / 1e6
+		}
+	}
+
+	// series are continuous, blocks and seconds without txs of the test are included with zero counts
+	if len(blocks) != 0 {
+		first, last := uint64(math.MaxUint64), uint64(0)
+		for number := range blocks {
+			first, last = min(first, number), max(last, number)
+		}
+		for number := first; number <= last; number++ {
+			metrics.blockSeries = append(metrics.blockSeries, block(number))
+		}
+	}
+	if len(seconds) != 0 {
+		first, last := int64(math.MaxInt64), int64(0)
+		for unix := range seconds {
+			first, last = min(first, unix), max(last, unix)
+		}
+		for unix := first; unix <= last; unix++ {
+			metrics.secondSeries = append(metrics.secondSeries, second(unix))
+		}
+	}
+}
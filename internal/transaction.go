@@ -2,12 +2,12 @@ package internal
 
 import (
 	"context"
+	"fmt"
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/ethclient"
-	"log"
 	"math/big"
+	"time"
 )
 
 type Transaction struct {
@@ -18,25 +18,27 @@ type Transaction struct {
 	sentBlock         uint64
 	minedBlock        uint64
 	sentTimestamp     int64
+	function          *Function    // contract function of the tx, nil for plain transfers
+	replacement       *Replacement // same nonce tx with bumped fee, nil if tx is not replaced
+	sendErr           error        // error returned by the node on send, tx wasn't accepted
+	sendTimeout       error        // send timed out, the node may have accepted the tx
+	sendLatency       time.Duration
+	endpoint          *Endpoint // endpoint the tx was sent through
+	gapLost           bool      // tx was sent, but stuck behind a nonce gap
 }
 
 func CreateAndSignTransaction(
-	client *ethclient.Client,
+	ctx context.Context,
+	client *RpcClient,
 	chainId int64,
 	sender *Sender,
 	receiver *common.Address,
 	valueToSend *big.Int,
 	data []byte,
+	gas *GasStrategy,
+	gasKey string,
 ) (*Transaction, error) {
-	tipCap, err := client.SuggestGasTipCap(context.Background()) // maxPriorityFeePerGas
-	if err != nil {
-		log.Fatal("error getting tipCap:", err)
-	}
-
-	feeCap, err := client.SuggestGasPrice(context.Background()) // maxFeePerGas
-	if err != nil {
-		log.Fatal("error getting feeCap:", err)
-	}
+	tipCap, feeCap := gas.fees() // maxPriorityFeePerGas, maxFeePerGas
 
 	dynTx := &types.DynamicFeeTx{
 		Nonce:     sender.Nonce,
@@ -50,21 +52,21 @@ func CreateAndSignTransaction(
 		dynTx.Data = data
 	}
 
-	gasLimit, err := client.EstimateGas(context.Background(), ethereum.CallMsg{
-		From: *sender.Address,
-		To:   receiver,
-		Data: data,
+	gasLimit, err := gas.gasLimit(ctx, client, gasKey, ethereum.CallMsg{
+		From:  *sender.Address,
+		To:    receiver,
+		Value: valueToSend,
+		Data:  data,
 	})
 	if err != nil {
-
-		log.Fatalf("Failed to estimate gas: %v", err)
+		return nil, err
 	}
 	dynTx.Gas = gasLimit
 
-	tx := types.NewTx(dynTx)
-
-	signer := types.NewLondonSigner(big.NewInt(chainId))
-	signedTx, err := types.SignTx(tx, signer, sender.PrivateKeyEcdsa)
+	signedTx, err := signTransaction(chainId, sender, types.NewTx(dynTx))
+	if err != nil {
+		return nil, err
+	}
 	sender.Nonce = sender.Nonce + 1
 
 	transaction := &Transaction{
@@ -74,3 +76,46 @@ func CreateAndSignTransaction(
 
 	return transaction, nil
 }
+
+// resignTransaction signs a copy of tx with the given nonce and fees.
+func resignTransaction(chainId int64, sender *Sender, tx *types.Transaction, nonce uint64, tipCap, feeCap *big.Int) (*types.Transaction, error) {
+	return signTransaction(chainId, sender, types.NewTx(&types.DynamicFeeTx{
+		Nonce:     nonce,
+		To:        tx.To(),
+		Value:     tx.Value(),
+		Gas:       tx.Gas(),
+		GasFeeCap: feeCap,
+		GasTipCap: tipCap,
+		Data:      tx.Data(),
+	}))
+}
+
+func signTransaction(chainId int64, sender *Sender, tx *types.Transaction) (*types.Transaction, error) {
+	signedTx, err := sender.Signer.SignTx(tx, *sender.Address, big.NewInt(chainId))
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign transaction: %w", err)
+	}
+
+	return signedTx, nil
+}
+
+// sendFailed checks whether the tx was rejected on send, a tx which send timed out is failed only when it wasn't mined.
+func (tx *Transaction) sendFailed() bool {
+	return tx.sendErr != nil || (tx.sendTimeout != nil && tx.receipt == nil)
+}
+
+// timeToInclude returns time (in ms) between tx send and block timestamp of the block where tx was mined.
+func (tx *Transaction) timeToInclude(blockTimes map[uint64]uint64) (int64, bool) {
+	if tx.receipt == nil || tx.sentTimestamp == 0 {
+		return 0, false
+	}
+
+	blockTime, exists := blockTimes[tx.receipt.BlockNumber.Uint64()]
+	if !exists {
+		return 0, false
+	}
+
+	// block timestamp has seconds precision, so tx may look included before it was sent
+	timeDiff := time.Unix(int64(blockTime), 0).Sub(time.UnixMilli(tx.sentTimestamp))
+	return max(timeDiff.Milliseconds(), 0), true
+}
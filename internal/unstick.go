@@ -0,0 +1,234 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+const (
+	UnstickPollIntervalSec = 1
+	UnstickGasLimit        = params.TxGas
+)
+
+var SendersNotUnstuck = errors.New("some senders still have pending or gapped transactions")
+
+// Unsticker clears stuck and gapped pending txs of configured senders.
+// Every nonce from the latest (mined) nonce up to the highest known pending/queued nonce
+// gets a 0-value self-transfer with bumped fee, which fills gaps and replaces stuck txs.
+type Unsticker struct {
+	config         Config
+	client         *RpcClient
+	feeBumpPercent int
+	extraNonces    uint64
+	timeout        time.Duration
+}
+
+// poolTx contains fees of a tx from txpool_contentFrom response.
+type poolTx struct {
+	GasPrice             *hexutil.Big `json:"gasPrice"`
+	MaxFeePerGas         *hexutil.Big `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas *hexutil.Big `json:"maxPriorityFeePerGas"`
+}
+
+// senderNonces contains nonce range of the sender to fill.
+type senderNonces struct {
+	sender   *Sender
+	latest   uint64
+	maxNonce uint64
+}
+
+func NewUnsticker(config Config, client *RpcClient, feeBumpPercent int, extraNonces uint64, timeout time.Duration) *Unsticker {
+	return &Unsticker{
+		config:         config,
+		client:         client,
+		feeBumpPercent: feeBumpPercent,
+		extraNonces:    extraNonces,
+		timeout:        timeout,
+	}
+}
+
+func (u *Unsticker) Start() error {
+	var toWait []senderNonces
+
+	senders, err := NewSenders(u.client, u.config.Senders)
+	if err != nil {
+		return err
+	}
+
+	for _, sender := range senders {
+		nonces, err := u.unstickSender(sender)
+		if err != nil {
+			fmt.Printf("Sender %s: %v\n", sender.Address.Hex(), err)
+			continue
+		}
+		if nonces != nil {
+			toWait = append(toWait, *nonces)
+		}
+	}
+
+	if len(toWait) == 0 {
+		fmt.Println("All senders are clean")
+		return nil
+	}
+
+	return u.waitClean(toWait)
+}
+
+// unstickSender sends cancel txs for every not mined nonce of the sender, returns nil if sender is clean.
+func (u *Unsticker) unstickSender(sender *Sender) (*senderNonces, error) {
+	latest, err := u.client.NonceAt(context.Background(), *sender.Address, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve latest nonce: %w", err)
+	}
+
+	pending, err := u.client.PendingNonceAt(context.Background(), *sender.Address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve pending nonce: %w", err)
+	}
+
+	poolTxs, err := u.poolTxs(sender)
+	if err != nil {
+		fmt.Printf("Sender %s: txpool_contentFrom is not available (%v), only nonces up to pending nonce + %d are filled\n",
+			sender.Address.Hex(), err, u.extraNonces)
+	}
+
+	// nonces to fill: [latest, maxNonce], maxNonce < latest means nothing to fill
+	maxNonce := pending + u.extraNonces
+	for nonce := range poolTxs {
+		maxNonce = max(maxNonce, nonce+1)
+	}
+	if maxNonce <= latest {
+		fmt.Printf("Sender %s: clean (nonce %d)\n", sender.Address.Hex(), latest)
+		return nil, nil
+	}
+	maxNonce--
+
+	fmt.Printf("Sender %s: latest nonce %d, pending nonce %d, filling nonces %d-%d\n",
+		sender.Address.Hex(), latest, pending, latest, maxNonce)
+
+	tipCap, err := u.client.SuggestGasTipCap(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("error getting tipCap: %w", err)
+	}
+	header, err := u.client.HeaderByNumber(context.Background(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("error getting latest header: %w", err)
+	}
+
+	tipCap = bumpFee(tipCap, u.feeBumpPercent)
+	feeCap := new(big.Int).Set(tipCap)
+	if header.BaseFee != nil {
+		feeCap.Add(feeCap, new(big.Int).Mul(header.BaseFee, big.NewInt(2)))
+	}
+
+	for nonce := latest; nonce <= maxNonce; nonce++ {
+		nonceTipCap, nonceFeeCap := tipCap, feeCap
+
+		// replacement must exceed fees of the tx in pool
+		if existing, exists := poolTxs[nonce]; exists {
+			existingTipCap, existingFeeCap := existing.fees()
+			nonceTipCap = bigMax(nonceTipCap, bumpFee(existingTipCap, u.feeBumpPercent))
+			nonceFeeCap = bigMax(nonceFeeCap, bumpFee(existingFeeCap, u.feeBumpPercent))
+		}
+		nonceFeeCap = bigMax(nonceFeeCap, nonceTipCap)
+
+		cancelTx, err := signTransaction(u.config.App.Node.ChainID, sender, types.NewTx(&types.DynamicFeeTx{
+			Nonce:     nonce,
+			To:        sender.Address,
+			Value:     big.NewInt(0),
+			Gas:       UnstickGasLimit,
+			GasFeeCap: nonceFeeCap,
+			GasTipCap: nonceTipCap,
+		}))
+		if err != nil {
+			return nil, err
+		}
+
+		if err = u.client.SendTransaction(context.Background(), cancelTx); err != nil {
+			fmt.Printf("Sender %s: failed to send cancel tx for nonce %d (%s): %v\n",
+				sender.Address.Hex(), nonce, classifySendError(err), err)
+		}
+	}
+
+	return &senderNonces{sender: sender, latest: latest, maxNonce: maxNonce}, nil
+}
+
+// poolTxs returns pending and queued txs of the sender by nonce (geth txpool namespace).
+func (u *Unsticker) poolTxs(sender *Sender) (map[uint64]poolTx, error) {
+	var content map[string]map[string]poolTx
+	err := u.client.CallContext(context.Background(), &content, "txpool_contentFrom", sender.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	poolTxs := make(map[uint64]poolTx)
+	for _, txs := range content {
+		for nonceStr, tx := range txs {
+			nonce, err := strconv.ParseUint(nonceStr, 10, 64)
+			if err != nil {
+				continue
+			}
+			poolTxs[nonce] = tx
+		}
+	}
+
+	return poolTxs, nil
+}
+
+// waitClean waits until all filled nonces are mined.
+func (u *Unsticker) waitClean(toWait []senderNonces) error {
+	deadline := time.Now().Add(u.timeout)
+
+	for len(toWait) != 0 && time.Now().Before(deadline) {
+		time.Sleep(UnstickPollIntervalSec * time.Second)
+
+		var stillWaiting []senderNonces
+		for _, nonces := range toWait {
+			latest, err := u.client.NonceAt(context.Background(), *nonces.sender.Address, nil)
+			if err != nil || latest <= nonces.maxNonce {
+				stillWaiting = append(stillWaiting, nonces)
+				continue
+			}
+			fmt.Printf("Sender %s: clean (nonce %d)\n", nonces.sender.Address.Hex(), latest)
+		}
+		toWait = stillWaiting
+	}
+
+	if len(toWait) != 0 {
+		for _, nonces := range toWait {
+			fmt.Printf("Sender %s: not clean after %s, nonces %d-%d\n",
+				nonces.sender.Address.Hex(), u.timeout, nonces.latest, nonces.maxNonce)
+		}
+		return SendersNotUnstuck
+	}
+
+	fmt.Println("All senders are clean")
+	return nil
+}
+
+// fees returns tip and fee cap of the pool tx, gas price is used for both for legacy txs.
+func (tx poolTx) fees() (*big.Int, *big.Int) {
+	if tx.MaxFeePerGas != nil && tx.MaxPriorityFeePerGas != nil {
+		return tx.MaxPriorityFeePerGas.ToInt(), tx.MaxFeePerGas.ToInt()
+	}
+	if tx.GasPrice != nil {
+		return tx.GasPrice.ToInt(), tx.GasPrice.ToInt()
+	}
+
+	return big.NewInt(0), big.NewInt(0)
+}
+
+func bigMax(a, b *big.Int) *big.Int {
+	if a.Cmp(b) >= 0 {
+		return a
+	}
+	return b
+}
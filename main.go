@@ -2,44 +2,90 @@ package main
 
 import (
 	"blockrush/internal"
+	"context"
 	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
-	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/spf13/cobra"
 )
 
 var configFile string
-var config *internal.Config
+var feeBumpPercent int
+var extraNonces uint64
+var unstickTimeoutSec int
 
 var rootCmd = &cobra.Command{
 	Use:   "blockrush",
 	Short: "Blockrush CLI tool",
 	Run: func(cmd *cobra.Command, args []string) {
-		var err error
-		config, err = internal.LoadConfig(configFile)
+		config, client := prepare()
+
+		endpoints, err := internal.NewEndpointPool(config.App.Node, client)
+		if err != nil {
+			log.Fatalf("Unable to prepare RPC endpoints: %v", err)
+		}
+
+		// SIGINT/SIGTERM stops sending, the second signal terminates immediately
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		go func() {
+			<-ctx.Done()
+			stop()
+		}()
+
+		runner := internal.NewRunner(*config, client, endpoints)
+		err = runner.Start(ctx)
+		if err != nil {
+			log.Fatalf("Runner encountered an error: %v", err)
+		}
+	},
+}
+
+var unstickCmd = &cobra.Command{
+	Use:   "unstick",
+	Short: "Clear stuck and gapped pending transactions of configured senders",
+	Long: "Compares latest and pending nonces of every configured sender, fills nonce gaps and cancels stuck " +
+		"transactions with 0-value self-transfers at a bumped fee, then waits until each account is clean.",
+	Run: func(cmd *cobra.Command, args []string) {
+		config, client := prepare()
+
+		unsticker := internal.NewUnsticker(*config, client, feeBumpPercent, extraNonces, time.Duration(unstickTimeoutSec)*time.Second)
+		err := unsticker.Start()
 		if err != nil {
-			log.Fatalf("Error loading configuration file: %v", err)
+			log.Fatalf("Unstick encountered an error: %v", err)
 		}
 	},
 }
 
 func init() {
 	rootCmd.PersistentFlags().StringVar(&configFile, "config", "", "path to config file")
+
+	unstickCmd.Flags().IntVar(&feeBumpPercent, "fee-bump-percent", 100, "fee increase of cancel transactions over suggested and stuck transaction fees")
+	unstickCmd.Flags().Uint64Var(&extraNonces, "extra-nonces", 0, "additional nonces above pending nonce to fill, when txpool_contentFrom is not available")
+	unstickCmd.Flags().IntVar(&unstickTimeoutSec, "timeout", 300, "time in seconds to wait until senders are clean")
+	rootCmd.AddCommand(unstickCmd)
 }
 
-func main() {
-	if err := rootCmd.Execute(); err != nil {
-		log.Fatalf("Command execution failed: %v", err)
+// prepare loads configuration file and connects to the node.
+func prepare() (*internal.Config, *internal.RpcClient) {
+	config, err := internal.LoadConfig(configFile)
+	if err != nil {
+		log.Fatalf("Error loading configuration file: %v", err)
 	}
 
-	client, err := ethclient.Dial(config.App.Node.RPCURL)
+	client, err := internal.DialNode(config.App.Node.RPCURL, config.App.Node)
 	if err != nil {
 		log.Fatalf("Unable to establish connection with Ethereum node: %v", err)
 	}
 
-	runner := internal.NewRunner(*config, client)
-	err = runner.Start()
-	if err != nil {
-		log.Fatalf("Runner encountered an error: %v", err)
+	return config, client
+}
+
+func main() {
+	if err := rootCmd.Execute(); err != nil {
+		log.Fatalf("Command execution failed: %v", err)
 	}
 }